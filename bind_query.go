@@ -0,0 +1,217 @@
+package supersaiyan
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrInvalidParameter is returned when a query parameter has a malformed key, operator or value.
+var ErrInvalidParameter = errors.New("invalid query parameter")
+
+// ValueType describes how a query parameter value is converted before it is bound.
+type ValueType int
+
+const (
+	// StringValue binds the raw string (default).
+	StringValue ValueType = iota
+	// IntValue binds a base-10 int64.
+	IntValue
+	// FloatValue binds a float64.
+	FloatValue
+	// BoolValue binds a bool parsed by strconv.ParseBool.
+	BoolValue
+	// TimeValue binds a time.Time parsed as RFC 3339 or as a YYYY-MM-DD date.
+	TimeValue
+)
+
+// QueryBinder converts URL query parameters into WHERE conditions.
+//
+// Parameters take the form "name=value" (equality) or "name[op]=value", where op is a
+// comparison ("eq", "neq", "gt", "gte", "lt", "lte"), a list operator ("in", "notIn"), a
+// pattern operator ("like", "notLike", "iLike", "notILike"), a range operator ("between",
+// "notBetween") or a null check ("is", "isNot"). List operators take comma-separated values,
+// and "is"/"isNot" accept "null", "true" or "false".
+// Names are resolved through Fields; names listed in Ignore are skipped, and values are
+// converted according to Types, which defaults to StringValue.
+type QueryBinder struct {
+	Fields FieldResolver
+	Types  map[string]ValueType
+	Ignore []string
+}
+
+// Bind converts url.Values into conditions, ordered by parameter name.
+//
+// Examples:
+//
+//	binder := QueryBinder{
+//		Fields: FieldMap{
+//			"status":  F("status", WithTable("u")),
+//			"age":     F("age", WithTable("u")),
+//			"country": F("country_code", WithTable("a")),
+//		},
+//		Types:  map[string]ValueType{"age": IntValue},
+//		Ignore: []string{"sort", "page"},
+//	}
+//	conds, err := binder.Bind(r.URL.Query()) // ?status=active&age[gte]=18&country[in]=US,CA
+func (b QueryBinder) Bind(values url.Values) ([]Condition, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var conditions []Condition
+	for _, key := range keys {
+		name, op, err := parseQueryKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(b.Ignore, name) {
+			continue
+		}
+
+		field, err := lookupField(b.Fields, name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, raw := range values[key] {
+			cond, err := queryCondition(field, b.Types[name], op, raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			conditions = append(conditions, cond)
+		}
+	}
+
+	return conditions, nil
+}
+
+// queryOperators lists the comparison operators accepted in query parameter keys.
+var queryOperators = map[string]exp.BooleanOperation{
+	"eq":       exp.EqOp,
+	"neq":      exp.NeqOp,
+	"gt":       exp.GtOp,
+	"gte":      exp.GteOp,
+	"lt":       exp.LtOp,
+	"lte":      exp.LteOp,
+	"in":       exp.InOp,
+	"notIn":    exp.NotInOp,
+	"is":       exp.IsOp,
+	"isNot":    exp.IsNotOp,
+	"like":     exp.LikeOp,
+	"notLike":  exp.NotLikeOp,
+	"iLike":    exp.ILikeOp,
+	"notILike": exp.NotILikeOp,
+}
+
+// parseQueryKey splits "name[op]" into its name and operator. A bare name means "eq".
+func parseQueryKey(key string) (string, string, error) {
+	name, rest, hasOp := strings.Cut(key, "[")
+	if !hasOp {
+		return key, "eq", nil
+	}
+
+	op, ok := strings.CutSuffix(rest, "]")
+	if !ok || name == "" || op == "" || strings.ContainsAny(op, "[]") {
+		return "", "", fmt.Errorf("%w: malformed key %q", ErrInvalidParameter, key)
+	}
+
+	return name, op, nil
+}
+
+// queryCondition builds the condition on field for a single parameter value.
+func queryCondition(field Field, vt ValueType, op, raw string) (Condition, error) {
+	switch op {
+	case "between", "notBetween":
+		bounds := strings.Split(raw, ",")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf(
+				"%w: %s expects two comma-separated values",
+				ErrInvalidParameter,
+				op,
+			)
+		}
+		start, err := vt.convert(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := vt.convert(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		return fieldRangeOp(stringToRangeOp(op), field, start, end), nil
+	}
+
+	boolOp, ok := queryOperators[op]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidParameter, op)
+	}
+
+	var value any
+	var err error
+	switch boolOp {
+	case exp.InOp, exp.NotInOp:
+		items := strings.Split(raw, ",")
+		list := make([]any, len(items))
+		for i, item := range items {
+			if list[i], err = vt.convert(item); err != nil {
+				return nil, err
+			}
+		}
+		value = list
+	case exp.IsOp, exp.IsNotOp:
+		switch strings.ToLower(raw) {
+		case "null":
+			value = nil
+		case "true":
+			value = true
+		case "false":
+			value = false
+		default:
+			return nil, fmt.Errorf("%w: %s expects null, true or false", ErrInvalidParameter, op)
+		}
+	default:
+		if value, err = vt.convert(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return fieldBoolOp(boolOp, field, value), nil
+}
+
+// convert parses a raw parameter value according to the ValueType.
+func (vt ValueType) convert(raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+
+	var value any
+	var err error
+	switch vt {
+	case IntValue:
+		value, err = strconv.ParseInt(raw, 10, 64)
+	case FloatValue:
+		value, err = strconv.ParseFloat(raw, 64)
+	case BoolValue:
+		value, err = strconv.ParseBool(raw)
+	case TimeValue:
+		value, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			value, err = time.Parse(time.DateOnly, raw)
+		}
+	default:
+		value = raw
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot convert %q", ErrInvalidParameter, raw)
+	}
+	return value, nil
+}
@@ -6,7 +6,7 @@ import "github.com/doug-martin/goqu/v9/exp"
 // This interface ensures type safety while allowing flexibility.
 type Condition interface {
 	// toExpression converts the condition to a goqu expression.
-	toExpression() exp.Expression
+	toExpression(rc *renderContext) exp.Expression
 }
 
 // Ensure our types implement Condition
@@ -14,19 +14,25 @@ var (
 	_ Condition = BoolOp{}
 	_ Condition = RangeOp{}
 	_ Condition = WhereGroup{}
+	_ Condition = Search{}
 )
 
 // toExpression for BoolOp
-func (bo BoolOp) toExpression() exp.Expression {
-	return bo.expression()
+func (bo BoolOp) toExpression(rc *renderContext) exp.Expression {
+	return bo.expression(rc)
 }
 
 // toExpression for RangeOp
-func (ro RangeOp) toExpression() exp.Expression {
-	return ro.expression()
+func (ro RangeOp) toExpression(rc *renderContext) exp.Expression {
+	return ro.expression(rc)
 }
 
 // toExpression for WhereGroup
-func (wg WhereGroup) toExpression() exp.Expression {
-	return wg.expression()
+func (wg WhereGroup) toExpression(rc *renderContext) exp.Expression {
+	return wg.expression(rc)
+}
+
+// toExpression for Search
+func (s Search) toExpression(rc *renderContext) exp.Expression {
+	return s.expression(rc)
 }
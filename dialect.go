@@ -0,0 +1,97 @@
+package supersaiyan
+
+// regexpSyntax is how a dialect matches regular expressions.
+type regexpSyntax int
+
+const (
+	// noRegexp means the dialect has no regular expression matching.
+	noRegexp regexpSyntax = iota
+	// regexpOperators are PostgreSQL's ~, !~, ~* and !~*.
+	regexpOperators
+	// regexpLikeFunction is MySQL's REGEXP_LIKE(value, pattern, match_type).
+	regexpLikeFunction
+	// regexpOperatorCaseSensitive is SQLite's REGEXP operator, backed by an application
+	// defined regexp() function, with no case-insensitive form.
+	regexpOperatorCaseSensitive
+)
+
+// dialectCapabilities lists the optional SQL features a dialect supports natively.
+// Features missing from a dialect are emulated where an equivalent exists, otherwise
+// rendering fails with ErrUnsupportedByDialect.
+type dialectCapabilities struct {
+	ILike                bool         // ILIKE operator
+	LikeBackslashEscape  bool         // backslash escapes LIKE wildcards without an ESCAPE clause
+	LikeBinary           bool         // LIKE is made case-sensitive as LIKE BINARY
+	Regexp               regexpSyntax // regular expression matching
+	NullsOrdering        bool         // NULLS FIRST / NULLS LAST in ORDER BY
+	AggregateFilter      bool         // FILTER (WHERE ...) on aggregates
+	Arrays               bool         // array parameters and operators (@>, &&, = ANY(?))
+	QuantifiedSubqueries bool         // comparisons with ANY / ALL (subquery)
+	FullJoin             bool         // FULL OUTER JOIN
+	NaturalJoin          bool         // NATURAL JOIN
+	LateralJoin          bool         // JOIN LATERAL (subquery)
+	JoinUsing            bool         // JOIN ... USING (columns)
+	CrossDatabase        bool         // database.schema.table names
+}
+
+// defaultCapabilities apply to dialects without their own entry, including goqu's default.
+var defaultCapabilities = dialectCapabilities{
+	ILike:                true,
+	LikeBackslashEscape:  true,
+	Regexp:               regexpOperators,
+	NullsOrdering:        true,
+	AggregateFilter:      true,
+	QuantifiedSubqueries: true,
+	FullJoin:             true,
+	NaturalJoin:          true,
+	LateralJoin:          true,
+	JoinUsing:            true,
+	CrossDatabase:        true,
+}
+
+// capabilities maps dialect names to their supported features.
+var capabilities = map[string]dialectCapabilities{
+	"postgres": {
+		ILike:                true,
+		LikeBackslashEscape:  true,
+		Regexp:               regexpOperators,
+		NullsOrdering:        true,
+		AggregateFilter:      true,
+		Arrays:               true,
+		QuantifiedSubqueries: true,
+		FullJoin:             true,
+		NaturalJoin:          true,
+		LateralJoin:          true,
+		JoinUsing:            true,
+	},
+	"mysql": {
+		LikeBackslashEscape:  true,
+		LikeBinary:           true,
+		Regexp:               regexpLikeFunction,
+		QuantifiedSubqueries: true,
+		NaturalJoin:          true,
+		LateralJoin:          true,
+		JoinUsing:            true,
+	},
+	"sqlite3": {
+		Regexp:          regexpOperatorCaseSensitive,
+		NullsOrdering:   true,
+		AggregateFilter: true,
+		FullJoin:        true,
+		NaturalJoin:     true,
+		JoinUsing:       true,
+	},
+	"sqlserver": {
+		QuantifiedSubqueries: true,
+		FullJoin:             true,
+		CrossDatabase:        true,
+	},
+}
+
+// capabilitiesOf returns the features supported by the dialect.
+func capabilitiesOf(dialect string) dialectCapabilities {
+	if caps, ok := capabilities[dialect]; ok {
+		return caps
+	}
+	return defaultCapabilities
+}
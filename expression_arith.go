@@ -0,0 +1,169 @@
+package supersaiyan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrInvalidExpression is returned when an expression is malformed, such as an operator
+// with the wrong number of operands.
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// ArithOp is the operator of an Arith expression.
+type ArithOp string
+
+const (
+	// AddOp adds its operands (+).
+	AddOp ArithOp = "+"
+	// SubOp subtracts each following operand from the first (-).
+	SubOp ArithOp = "-"
+	// MulOp multiplies its operands (*).
+	MulOp ArithOp = "*"
+	// DivOp divides the first operand by each following operand (/).
+	DivOp ArithOp = "/"
+	// ModOp is the remainder of the first operand divided by the second (%).
+	ModOp ArithOp = "%"
+	// ConcatOp concatenates strings: CONCAT() on MySQL, + on SQL Server and || elsewhere.
+	ConcatOp ArithOp = "||"
+	// NegOp negates its single operand (unary -).
+	NegOp ArithOp = "neg"
+)
+
+// Arith represents an arithmetic or string concatenation expression. Operands can be any
+// value or expression, including other Arith nodes; every node renders inside its own
+// parentheses so nesting never depends on operator precedence.
+type Arith struct {
+	Op       ArithOp `json:"arith"    yaml:"arith"`
+	Operands []any   `json:"operands" yaml:"operands"`
+}
+
+// expression converts the Arith to a goqu literal expression for the query's dialect.
+func (a Arith) expression(rc *renderContext) exp.LiteralExpression {
+	switch a.Op {
+	case AddOp, SubOp, MulOp, DivOp, ConcatOp:
+		if len(a.Operands) < 2 {
+			return a.invalid(rc, "at least two operands")
+		}
+	case ModOp:
+		if len(a.Operands) != 2 {
+			return a.invalid(rc, "two operands")
+		}
+	case NegOp:
+		if len(a.Operands) != 1 {
+			return a.invalid(rc, "one operand")
+		}
+		return goqu.L("(-?)", handleAny(rc, a.Operands[0]))
+	default:
+		rc.fail(fmt.Errorf("%w: unknown operator %q", ErrInvalidExpression, a.Op))
+		return goqu.L("NULL")
+	}
+
+	args := make([]any, len(a.Operands))
+	placeholders := make([]string, len(a.Operands))
+	for i, operand := range a.Operands {
+		args[i] = handleAny(rc, operand)
+		placeholders[i] = "?"
+	}
+
+	if a.Op == ConcatOp {
+		switch rc.dialect {
+		case "mysql":
+			return goqu.L("CONCAT("+strings.Join(placeholders, ", ")+")", args...)
+		case "sqlserver":
+			return goqu.L("("+strings.Join(placeholders, " + ")+")", args...)
+		}
+	}
+
+	return goqu.L("("+strings.Join(placeholders, " "+string(a.Op)+" ")+")", args...)
+}
+
+// invalid records an operand count error and returns a placeholder expression.
+func (a Arith) invalid(rc *renderContext, want string) exp.LiteralExpression {
+	rc.fail(fmt.Errorf(
+		"%w: %s expects %s, got %d",
+		ErrInvalidExpression,
+		a.Op,
+		want,
+		len(a.Operands),
+	))
+	return goqu.L("NULL")
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Arith.
+func (a *Arith) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Op       ArithOp           `json:"arith"`
+		Operands []json.RawMessage `json:"operands"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.Op = aux.Op
+	a.Operands = make([]any, len(aux.Operands))
+	for i, operandData := range aux.Operands {
+		operand, err := unmarshalValue(operandData)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal operand %d: %w", i, err)
+		}
+		a.Operands[i] = operand
+	}
+
+	return nil
+}
+
+// Add creates an addition: (a + b + ...).
+//
+// Examples:
+//
+//	Add(F("subtotal", WithTable("o")), F("shipping", WithTable("o")))
+func Add(operands ...any) Arith {
+	return Arith{Op: AddOp, Operands: operands}
+}
+
+// Sub creates a subtraction: (a - b - ...).
+//
+// Examples:
+//
+//	Sub(Mul(F("price"), F("quantity")), F("discount")) // (("price" * "quantity") - "discount")
+func Sub(operands ...any) Arith {
+	return Arith{Op: SubOp, Operands: operands}
+}
+
+// Mul creates a multiplication: (a * b * ...).
+func Mul(operands ...any) Arith {
+	return Arith{Op: MulOp, Operands: operands}
+}
+
+// Div creates a division: (a / b / ...).
+func Div(operands ...any) Arith {
+	return Arith{Op: DivOp, Operands: operands}
+}
+
+// Mod creates a remainder: (a % b).
+func Mod(dividend, divisor any) Arith {
+	return Arith{Op: ModOp, Operands: []any{dividend, divisor}}
+}
+
+// Neg creates a negation: (-a).
+func Neg(operand any) Arith {
+	return Arith{Op: NegOp, Operands: []any{operand}}
+}
+
+// Concat creates a string concatenation rendered for the query's dialect.
+//
+// Examples:
+//
+//	Concat(F("first_name", WithTable("u")), " ", F("last_name", WithTable("u")))
+//	// postgres/sqlite3: ("u"."first_name" || ? || "u"."last_name")
+//	// mysql:            CONCAT("u"."first_name", ?, "u"."last_name")
+//	// sqlserver:        ("u"."first_name" + ? + "u"."last_name")
+func Concat(operands ...any) Arith {
+	return Arith{Op: ConcatOp, Operands: operands}
+}
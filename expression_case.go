@@ -21,15 +21,15 @@ type WhenThen struct {
 }
 
 // expression converts the Case to a goqu case expression.
-func (c Case) expression() exp.CaseExpression {
+func (c Case) expression(rc *renderContext) exp.CaseExpression {
 	caseExpr := goqu.Case()
 
 	for _, cond := range c.Conditions {
-		caseExpr = caseExpr.When(handleAny(cond.When), handleAny(cond.Then))
+		caseExpr = caseExpr.When(handleAny(rc, cond.When), handleAny(rc, cond.Then))
 	}
 
 	if c.Else != nil {
-		caseExpr = caseExpr.Else(handleAny(c.Else))
+		caseExpr = caseExpr.Else(handleAny(rc, c.Else))
 	}
 
 	return caseExpr
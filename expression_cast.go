@@ -0,0 +1,143 @@
+package supersaiyan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Cast represents a CAST(value AS type) conversion using a portable type name:
+// int, bigint, decimal(p,s), text, date, timestamp, boolean or json.
+// The type is mapped to the query dialect's own type names when rendered.
+type Cast struct {
+	Exp  any    `json:"cast" yaml:"cast"`
+	Type string `json:"as"   yaml:"as"`
+}
+
+// decimalTypePattern matches decimal, decimal(p) and decimal(p,s).
+var decimalTypePattern = regexp.MustCompile(`^decimal(?:\((\d+)(?:,(\d+))?\))?$`)
+
+// castTypes maps portable type names to the CAST target type of each dialect.
+// The "" entry is used for dialects without their own entry.
+var castTypes = map[string]map[string]string{
+	"int": {
+		"": "INTEGER", "mysql": "SIGNED", "sqlserver": "INT",
+	},
+	"bigint": {
+		"": "BIGINT", "mysql": "SIGNED", "sqlite3": "INTEGER",
+	},
+	"text": {
+		"": "TEXT", "mysql": "CHAR", "sqlserver": "NVARCHAR(MAX)",
+	},
+	"date": {
+		"": "DATE",
+	},
+	"timestamp": {
+		"": "TIMESTAMP", "mysql": "DATETIME", "sqlserver": "DATETIME2",
+	},
+	"boolean": {
+		"": "BOOLEAN", "mysql": "UNSIGNED", "sqlite3": "INTEGER", "sqlserver": "BIT",
+	},
+	"json": {
+		"": "JSON", "postgres": "JSONB", "sqlserver": "NVARCHAR(MAX)",
+	},
+}
+
+// sqliteCastFunctions convert to types SQLite has no CAST target for.
+var sqliteCastFunctions = map[string]string{
+	"date":      "DATE",
+	"timestamp": "DATETIME",
+	"json":      "JSON",
+}
+
+// expression converts the Cast to a goqu literal expression for the query's dialect.
+func (c Cast) expression(rc *renderContext) exp.LiteralExpression {
+	value := handleAny(rc, c.Exp)
+	typeName := strings.ToLower(strings.Join(strings.Fields(c.Type), ""))
+
+	if rc.dialect == "sqlite3" {
+		if fn, ok := sqliteCastFunctions[typeName]; ok {
+			return goqu.L(fn+"(?)", value)
+		}
+	}
+
+	sqlType, err := castType(rc.dialect, typeName)
+	if err != nil {
+		rc.fail(err)
+		return goqu.L("NULL")
+	}
+
+	return goqu.L("CAST(? AS "+sqlType+")", value)
+}
+
+// castType returns the dialect's type name for a normalized portable type name.
+func castType(dialect, typeName string) (string, error) {
+	if m := decimalTypePattern.FindStringSubmatch(typeName); m != nil {
+		name := "DECIMAL"
+		switch dialect {
+		case "postgres":
+			name = "NUMERIC"
+		case "sqlite3":
+			return "NUMERIC", nil
+		}
+
+		switch {
+		case m[2] != "":
+			return fmt.Sprintf("%s(%s,%s)", name, m[1], m[2]), nil
+		case m[1] != "":
+			return fmt.Sprintf("%s(%s)", name, m[1]), nil
+		default:
+			return name, nil
+		}
+	}
+
+	types, ok := castTypes[typeName]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown cast type %q", ErrInvalidExpression, typeName)
+	}
+	if sqlType, ok := types[dialect]; ok {
+		return sqlType, nil
+	}
+	return types[""], nil
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Cast.
+func (c *Cast) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Exp  json.RawMessage `json:"cast"`
+		Type string          `json:"as"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Type = aux.Type
+	if len(aux.Exp) > 0 {
+		value, err := unmarshalValue(aux.Exp)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal cast value: %w", err)
+		}
+		c.Exp = value
+	}
+
+	return nil
+}
+
+// CastAs creates a CAST expression to a portable type.
+//
+// Examples:
+//
+//	CastAs(F("created_at", WithTable("o")), "date")      // CAST("o"."created_at" AS DATE)
+//	CastAs(F("amount", WithTable("o")), "decimal(10,2)") // CAST("o"."amount" AS NUMERIC(10,2)) on postgres
+//	CastAs(F("id", WithTable("u")), "text")              // CAST("u"."id" AS CHAR) on mysql
+func CastAs(value any, typeName string) Cast {
+	return Cast{
+		Exp:  value,
+		Type: typeName,
+	}
+}
@@ -12,15 +12,15 @@ type Coalesce struct {
 }
 
 // expression converts the Coalesce to a goqu SQL function expression.
-func (co Coalesce) expression() exp.SQLFunctionExpression {
+func (co Coalesce) expression(rc *renderContext) exp.SQLFunctionExpression {
 	fields := make([]any, 0, len(co.Fields)+1)
 
 	for _, f := range co.Fields {
-		fields = append(fields, f.expression())
+		fields = append(fields, f.expression(rc))
 	}
 
 	if co.DefaultValue != nil {
-		fields = append(fields, handleAny(co.DefaultValue))
+		fields = append(fields, handleAny(rc, co.DefaultValue))
 	}
 
 	return goqu.COALESCE(fields...)
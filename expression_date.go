@@ -0,0 +1,374 @@
+package supersaiyan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// DateOp is the operation of a DateFunc expression.
+type DateOp string
+
+const (
+	// DateNowOp is the database's current timestamp.
+	DateNowOp DateOp = "now"
+	// DateTruncOp truncates a timestamp to the start of its unit.
+	DateTruncOp DateOp = "trunc"
+	// DateAddOp adds Amount units to a timestamp.
+	DateAddOp DateOp = "add"
+	// DateSubOp subtracts Amount units from a timestamp.
+	DateSubOp DateOp = "sub"
+	// DateExtractOp extracts a unit (year, month, ...) from a timestamp as a number.
+	DateExtractOp DateOp = "extract"
+)
+
+// DateFunc represents a portable date/time expression rendered with each dialect's functions.
+// Unit is one of year, month, week, day, hour, minute or second; weeks start on Monday.
+type DateFunc struct {
+	Op     DateOp `json:"date"             yaml:"date"`
+	Exp    any    `json:"exp,omitempty"    yaml:"exp,omitempty"`
+	Unit   string `json:"unit,omitempty"   yaml:"unit,omitempty"`
+	Amount int    `json:"amount,omitempty" yaml:"amount,omitempty"`
+}
+
+// dateUnits lists the supported units in order of decreasing size.
+var dateUnits = []string{"year", "month", "week", "day", "hour", "minute", "second"}
+
+// mysqlTruncFormats are DATE_FORMAT patterns that truncate to a unit on MySQL.
+var mysqlTruncFormats = map[string]string{
+	"year":   "%Y-01-01",
+	"month":  "%Y-%m-01",
+	"day":    "%Y-%m-%d",
+	"hour":   "%Y-%m-%d %H:00:00",
+	"minute": "%Y-%m-%d %H:%i:00",
+	"second": "%Y-%m-%d %H:%i:%s",
+}
+
+// sqliteFormats are strftime patterns for each unit on SQLite: a truncation pattern and
+// the pattern extracting the unit as a number. Weeks are computed separately.
+var sqliteFormats = map[string][2]string{
+	"year":   {"%Y-01-01 00:00:00", "%Y"},
+	"month":  {"%Y-%m-01 00:00:00", "%m"},
+	"week":   {"", ""},
+	"day":    {"%Y-%m-%d 00:00:00", "%d"},
+	"hour":   {"%Y-%m-%d %H:00:00", "%H"},
+	"minute": {"%Y-%m-%d %H:%M:00", "%M"},
+	"second": {"%Y-%m-%d %H:%M:%S", "%S"},
+}
+
+// expression converts the DateFunc to a goqu literal expression for the query's dialect.
+func (d DateFunc) expression(rc *renderContext) exp.LiteralExpression {
+	if d.Op == DateNowOp {
+		return goqu.L("CURRENT_TIMESTAMP")
+	}
+
+	unit := strings.ToLower(d.Unit)
+	if !isDateUnit(unit) {
+		rc.fail(fmt.Errorf("%w: unknown date unit %q", ErrInvalidExpression, d.Unit))
+		return goqu.L("NULL")
+	}
+	value := handleAny(rc, d.Exp)
+
+	switch d.Op {
+	case DateTruncOp:
+		return dateTrunc(rc, unit, value)
+	case DateAddOp:
+		return dateAdd(rc.dialect, unit, d.Amount, value)
+	case DateSubOp:
+		return dateAdd(rc.dialect, unit, -d.Amount, value)
+	case DateExtractOp:
+		return dateExtract(rc.dialect, unit, value)
+	default:
+		rc.fail(fmt.Errorf("%w: unknown date operation %q", ErrInvalidExpression, d.Op))
+		return goqu.L("NULL")
+	}
+}
+
+// dateTrunc renders the truncation of value to the start of unit.
+func dateTrunc(rc *renderContext, unit string, value exp.Expression) exp.LiteralExpression {
+	switch rc.dialect {
+	case "mysql":
+		if unit == "week" {
+			return goqu.L(
+				"CAST(DATE_SUB(DATE(?), INTERVAL WEEKDAY(?) DAY) AS DATETIME)",
+				value,
+				value,
+			)
+		}
+		return goqu.L("CAST(DATE_FORMAT(?, ?) AS DATETIME)", value, mysqlTruncFormats[unit])
+
+	case "sqlite3":
+		if unit == "week" {
+			return goqu.L("DATETIME(?, '-6 days', 'weekday 1', 'start of day')", value)
+		}
+		return goqu.L("STRFTIME(?, ?)", sqliteFormats[unit][0], value)
+
+	case "sqlserver":
+		switch unit {
+		case "week":
+			// DATEDIFF counts Sunday boundaries, so shift back a day to keep Sunday in its week
+			return goqu.L("DATEADD(week, DATEDIFF(week, 0, DATEADD(day, -1, ?)), 0)", value)
+		case "second":
+			rc.fail(fmt.Errorf(
+				"%w: truncating to seconds on %s",
+				ErrUnsupportedByDialect,
+				rc.dialect,
+			))
+			return goqu.L("NULL")
+		}
+		return goqu.L(fmt.Sprintf("DATEADD(%[1]s, DATEDIFF(%[1]s, 0, ?), 0)", unit), value)
+
+	default:
+		return goqu.L(fmt.Sprintf("DATE_TRUNC('%s', ?)", unit), value)
+	}
+}
+
+// dateAdd renders value shifted by amount units; the amount is a Go int and safe to inline.
+func dateAdd(dialect, unit string, amount int, value exp.Expression) exp.LiteralExpression {
+	switch dialect {
+	case "mysql":
+		return goqu.L(
+			fmt.Sprintf("DATE_ADD(?, INTERVAL %d %s)", amount, strings.ToUpper(unit)),
+			value,
+		)
+
+	case "sqlite3":
+		if unit == "week" {
+			unit, amount = "day", amount*7
+		}
+		return goqu.L(fmt.Sprintf("DATETIME(?, '%+d %ss')", amount, unit), value)
+
+	case "sqlserver":
+		return goqu.L(fmt.Sprintf("DATEADD(%s, %d, ?)", unit, amount), value)
+
+	default:
+		return goqu.L(fmt.Sprintf("(? + INTERVAL '%d %s')", amount, unit), value)
+	}
+}
+
+// dateExtract renders the numeric unit of value. Weeks are ISO 8601 weeks on every dialect:
+// they start on Monday and week 1 holds the year's first Thursday.
+func dateExtract(dialect, unit string, value exp.Expression) exp.LiteralExpression {
+	switch dialect {
+	case "sqlite3":
+		if unit == "week" {
+			// the day of the year of the week's Thursday numbers the week
+			return goqu.L(
+				"((CAST(STRFTIME('%j', DATE(?, '-3 days', 'weekday 4')) AS INTEGER) - 1) / 7 + 1)",
+				value,
+			)
+		}
+		return goqu.L("CAST(STRFTIME(?, ?) AS INTEGER)", sqliteFormats[unit][1], value)
+	case "sqlserver":
+		if unit == "week" {
+			unit = "iso_week"
+		}
+		return goqu.L(fmt.Sprintf("DATEPART(%s, ?)", unit), value)
+	case "mysql":
+		if unit == "week" {
+			// mode 3 numbers weeks starting on Monday, with week 1 having 4 or more days
+			return goqu.L("WEEK(?, 3)", value)
+		}
+		fallthrough
+	default:
+		return goqu.L(fmt.Sprintf("EXTRACT(%s FROM ?)", strings.ToUpper(unit)), value)
+	}
+}
+
+// isDateUnit reports whether unit is a supported date unit.
+func isDateUnit(unit string) bool {
+	for _, u := range dateUnits {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for DateFunc.
+func (d *DateFunc) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Op     DateOp          `json:"date"`
+		Exp    json.RawMessage `json:"exp,omitempty"`
+		Unit   string          `json:"unit,omitempty"`
+		Amount int             `json:"amount,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	d.Op = aux.Op
+	d.Unit = aux.Unit
+	d.Amount = aux.Amount
+	if len(aux.Exp) > 0 {
+		value, err := unmarshalValue(aux.Exp)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal date expression: %w", err)
+		}
+		d.Exp = value
+	}
+
+	return nil
+}
+
+// Now creates the database's current timestamp (CURRENT_TIMESTAMP).
+func Now() DateFunc {
+	return DateFunc{Op: DateNowOp}
+}
+
+// DateTrunc truncates a timestamp to the start of the unit.
+//
+// Examples:
+//
+//	DateTrunc("month", F("created_at", WithTable("o")))
+//	// postgres: DATE_TRUNC('month', "o"."created_at")
+func DateTrunc(unit string, value any) DateFunc {
+	return DateFunc{Op: DateTruncOp, Unit: unit, Exp: value}
+}
+
+// DateAdd adds amount units to a timestamp.
+//
+// Examples:
+//
+//	DateAdd(F("created_at", WithTable("o")), 30, "day")
+//	// mysql: DATE_ADD("o"."created_at", INTERVAL 30 DAY)
+func DateAdd(value any, amount int, unit string) DateFunc {
+	return DateFunc{Op: DateAddOp, Exp: value, Amount: amount, Unit: unit}
+}
+
+// DateSub subtracts amount units from a timestamp.
+//
+// Examples:
+//
+//	DateSub(Now(), 1, "week")
+func DateSub(value any, amount int, unit string) DateFunc {
+	return DateFunc{Op: DateSubOp, Exp: value, Amount: amount, Unit: unit}
+}
+
+// Extract returns a unit of a timestamp as a number. Weeks are numbered as ISO 8601 weeks.
+//
+// Examples:
+//
+//	Extract("year", F("created_at", WithTable("o"))) // EXTRACT(YEAR FROM "o"."created_at")
+func Extract(unit string, value any) DateFunc {
+	return DateFunc{Op: DateExtractOp, Unit: unit, Exp: value}
+}
+
+// RelativeTime is a point in time relative to the builder's clock, resolved when the query
+// is rendered and bound as a time.Time value. Documents write it as {"relative": "now-30d"};
+// plain strings are never read as relative times. The syntax is:
+//
+//	now            the current time
+//	now-30d        30 days ago (units: s, m, h, d, w, M for months, y)
+//	now-1h+15m     offsets can be chained
+//	startOf:month  the start of the current month (year, month, week, day, hour or minute)
+//	startOf:day-1d the start of yesterday
+type RelativeTime string
+
+// relativeTimePattern matches the RelativeTime syntax.
+var relativeTimePattern = regexp.MustCompile(`^(now|startOf:[A-Za-z]+)((?:[+-]\d+[A-Za-z])*)$`)
+
+// relativeOffsetPattern matches a single offset such as -30d.
+var relativeOffsetPattern = regexp.MustCompile(`([+-])(\d+)([A-Za-z])`)
+
+// MarshalJSON implements custom JSON marshaling for RelativeTime.
+func (r RelativeTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Relative string `json:"relative"`
+	}{Relative: string(r)})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for RelativeTime.
+func (r *RelativeTime) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Relative string `json:"relative"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	*r = RelativeTime(aux.Relative)
+	return nil
+}
+
+// MarshalYAML implements custom YAML marshaling for RelativeTime.
+func (r RelativeTime) MarshalYAML() (interface{}, error) {
+	return map[string]string{"relative": string(r)}, nil
+}
+
+// Resolve computes the time relative to now.
+func (r RelativeTime) Resolve(now time.Time) (time.Time, error) {
+	m := relativeTimePattern.FindStringSubmatch(string(r))
+	if m == nil {
+		return time.Time{}, fmt.Errorf("%w: invalid relative time %q", ErrInvalidExpression, r)
+	}
+
+	t := now
+	if unit, ok := strings.CutPrefix(m[1], "startOf:"); ok {
+		var err error
+		if t, err = startOf(now, unit); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	for _, offset := range relativeOffsetPattern.FindAllStringSubmatch(m[2], -1) {
+		n, err := strconv.Atoi(offset[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid offset %q", ErrInvalidExpression, offset[0])
+		}
+		if offset[1] == "-" {
+			n = -n
+		}
+
+		switch offset[3] {
+		case "s":
+			t = t.Add(time.Duration(n) * time.Second)
+		case "m":
+			t = t.Add(time.Duration(n) * time.Minute)
+		case "h":
+			t = t.Add(time.Duration(n) * time.Hour)
+		case "d":
+			t = t.AddDate(0, 0, n)
+		case "w":
+			t = t.AddDate(0, 0, 7*n)
+		case "M":
+			t = t.AddDate(0, n, 0)
+		case "y":
+			t = t.AddDate(n, 0, 0)
+		default:
+			return time.Time{}, fmt.Errorf("%w: unknown offset unit in %q", ErrInvalidExpression, r)
+		}
+	}
+
+	return t, nil
+}
+
+// startOf truncates t to the start of the unit in t's location. Weeks start on Monday.
+func startOf(t time.Time, unit string) (time.Time, error) {
+	y, mo, d := t.Date()
+	loc := t.Location()
+
+	switch strings.ToLower(unit) {
+	case "year":
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, loc), nil
+	case "month":
+		return time.Date(y, mo, 1, 0, 0, 0, 0, loc), nil
+	case "week":
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, mo, d-daysSinceMonday, 0, 0, 0, 0, loc), nil
+	case "day":
+		return time.Date(y, mo, d, 0, 0, 0, 0, loc), nil
+	case "hour":
+		return time.Date(y, mo, d, t.Hour(), 0, 0, 0, loc), nil
+	case "minute":
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), 0, 0, loc), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: unknown startOf unit %q", ErrInvalidExpression, unit)
+	}
+}
@@ -19,7 +19,7 @@ type Field struct {
 
 // expression converts the Field to a goqu expression.
 // It handles aliased fields, complex expressions, and simple column references.
-func (f Field) expression() exp.Expression {
+func (f Field) expression(rc *renderContext) exp.Expression {
 	if f.Exp != nil {
 		var opt handleAnyOption
 		if f.aliased() {
@@ -28,11 +28,11 @@ func (f Field) expression() exp.Expression {
 			opt = withAlias(f.Name)
 		}
 
-		return handleAny(f.Exp, opt)
+		return handleAny(rc, f.Exp, opt)
 	}
 
 	if f.aliased() {
-		return f.aliasedExpression()
+		return f.aliasedExpression(rc)
 	}
 
 	return f.identifierExpression()
@@ -49,9 +49,9 @@ func (f Field) identifierExpression() exp.IdentifierExpression {
 }
 
 // aliasedExpression returns the field expression with an alias.
-func (f Field) aliasedExpression() exp.Expression {
+func (f Field) aliasedExpression(rc *renderContext) exp.Expression {
 	if f.Exp != nil {
-		return handleAny(f.Exp, withAlias(f.FieldAlias))
+		return handleAny(rc, f.Exp, withAlias(f.FieldAlias))
 	}
 	return f.identifierExpression().As(f.FieldAlias)
 }
@@ -0,0 +1,271 @@
+package supersaiyan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrFunctionNotAllowed is returned when a Func names a function not registered for the dialect.
+var ErrFunctionNotAllowed = errors.New("function not allowed")
+
+// Func represents a SQL function call such as COUNT(DISTINCT x) or SUM(x) FILTER (WHERE ...).
+// Only functions registered for the query's dialect can be rendered (see RegisterFunctions).
+// The registry restricts function names only: arguments are rendered as given, Literal ones
+// included.
+type Func struct {
+	Name     string `json:"func"               yaml:"func"`
+	Args     []any  `json:"args,omitempty"     yaml:"args,omitempty"`
+	Distinct bool   `json:"distinct,omitempty" yaml:"distinct,omitempty"`
+	Filter   any    `json:"filter,omitempty"   yaml:"filter,omitempty"` // Should contain a Condition
+}
+
+// funcNamePattern matches plain SQL function names.
+var funcNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// commonFunctions are allowed on every dialect.
+var commonFunctions = []string{
+	"COUNT", "SUM", "AVG", "MIN", "MAX",
+	"COALESCE", "NULLIF", "LOWER", "UPPER", "TRIM", "LTRIM", "RTRIM", "REPLACE",
+	"ABS", "ROUND", "FLOOR", "CEILING",
+}
+
+var (
+	functionsMu sync.RWMutex
+	functions   = map[string]map[string]bool{
+		"": functionSet(commonFunctions),
+		"postgres": functionSet(
+			commonFunctions,
+			"LENGTH", "CHAR_LENGTH", "STRING_AGG", "ARRAY_AGG", "CEIL", "NOW",
+		),
+		"mysql": functionSet(
+			commonFunctions,
+			"LENGTH", "CHAR_LENGTH", "GROUP_CONCAT", "IFNULL", "CEIL", "NOW",
+		),
+		"sqlite3":   functionSet(commonFunctions, "LENGTH", "GROUP_CONCAT", "IFNULL"),
+		"sqlserver": functionSet(commonFunctions, "LEN", "STRING_AGG", "ISNULL", "GETDATE"),
+	}
+)
+
+// functionSet builds a set of upper-case function names.
+func functionSet(base []string, extra ...string) map[string]bool {
+	set := make(map[string]bool, len(base)+len(extra))
+	for _, names := range [][]string{base, extra} {
+		for _, name := range names {
+			set[strings.ToUpper(name)] = true
+		}
+	}
+	return set
+}
+
+// RegisterFunctions allows additional SQL functions in Func expressions for a dialect. It
+// restricts function names, not their arguments. Names are case-insensitive. Dialects
+// without an entry fall back to the common functions.
+//
+// Examples:
+//
+//	RegisterFunctions("postgres", "date_trunc", "jsonb_array_length")
+//	RegisterFunctions("mysql", "DATE_FORMAT")
+func RegisterFunctions(dialect string, names ...string) {
+	functionsMu.Lock()
+	defer functionsMu.Unlock()
+
+	set, ok := functions[dialect]
+	if !ok {
+		set = functionSet(commonFunctions)
+		functions[dialect] = set
+	}
+	for _, name := range names {
+		set[strings.ToUpper(name)] = true
+	}
+}
+
+// functionAllowed reports whether the function is registered for the dialect.
+func functionAllowed(dialect, name string) bool {
+	functionsMu.RLock()
+	defer functionsMu.RUnlock()
+
+	set, ok := functions[dialect]
+	if !ok {
+		set = functions[""]
+	}
+	return set[strings.ToUpper(name)]
+}
+
+// expression converts the Func to a goqu literal expression.
+// Without FILTER support the filter is applied by wrapping each argument in
+// CASE WHEN filter THEN arg END, which aggregates ignore when NULL.
+func (f Func) expression(rc *renderContext) exp.LiteralExpression {
+	name := strings.ToUpper(f.Name)
+	if !funcNamePattern.MatchString(name) || !functionAllowed(rc.dialect, name) {
+		rc.fail(fmt.Errorf("%w: %q on %q", ErrFunctionNotAllowed, f.Name, rc.dialect))
+		return goqu.L("NULL")
+	}
+	if f.Distinct && len(f.Args) == 0 {
+		rc.fail(fmt.Errorf("%w: DISTINCT %s needs arguments", ErrInvalidExpression, name))
+		return goqu.L("NULL")
+	}
+
+	var filter exp.Expression
+	if f.Filter != nil {
+		filter = handleAny(rc, f.Filter)
+	}
+	emulateFilter := filter != nil && !capabilitiesOf(rc.dialect).AggregateFilter
+
+	args := make([]any, 0, len(f.Args)+1)
+	placeholders := make([]string, 0, len(f.Args))
+	for _, arg := range f.Args {
+		argExpr := handleAny(rc, arg)
+		if emulateFilter {
+			argExpr = goqu.Case().When(filter, argExpr)
+		}
+		args = append(args, argExpr)
+		placeholders = append(placeholders, "?")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('(')
+	if f.Distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	// Only COUNT counts rows without arguments; other functions are called as NAME()
+	switch {
+	case len(placeholders) > 0:
+		sb.WriteString(strings.Join(placeholders, ", "))
+	case name != "COUNT":
+	case emulateFilter:
+		sb.WriteString("?")
+		args = append(args, goqu.Case().When(filter, 1))
+	default:
+		sb.WriteByte('*')
+	}
+	sb.WriteByte(')')
+
+	if filter != nil && !emulateFilter {
+		sb.WriteString(" FILTER (WHERE ?)")
+		args = append(args, filter)
+	}
+
+	return goqu.L(sb.String(), args...)
+}
+
+// FilterBy restricts the rows an aggregate sees with FILTER (WHERE ...).
+// Multiple conditions are combined with AND.
+//
+// Examples:
+//
+//	Count().FilterBy(Eq("status", "o", "paid"))
+//	Sum(F("amount", WithTable("o"))).FilterBy(Gt("amount", "o", 0), IsNull("refunded_at", "o"))
+func (f Func) FilterBy(conditions ...Condition) Func {
+	switch len(conditions) {
+	case 0:
+		f.Filter = nil
+	case 1:
+		f.Filter = conditions[0]
+	default:
+		group := make([]any, len(conditions))
+		for i, cond := range conditions {
+			group[i] = cond
+		}
+		f.Filter = And(group...)
+	}
+	return f
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for Func.
+func (f *Func) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Name     string            `json:"func"`
+		Args     []json.RawMessage `json:"args,omitempty"`
+		Distinct bool              `json:"distinct,omitempty"`
+		Filter   json.RawMessage   `json:"filter,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	f.Name = aux.Name
+	f.Distinct = aux.Distinct
+
+	f.Args = make([]any, len(aux.Args))
+	for i, argData := range aux.Args {
+		arg, err := unmarshalValue(argData)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal func arg %d: %w", i, err)
+		}
+		f.Args[i] = arg
+	}
+	if len(f.Args) == 0 {
+		f.Args = nil
+	}
+
+	if len(aux.Filter) > 0 {
+		filter, err := unmarshalCondition(aux.Filter)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal func filter: %w", err)
+		}
+		f.Filter = filter
+	}
+
+	return nil
+}
+
+// Fn creates a call to a registered SQL function.
+//
+// Examples:
+//
+//	Fn("lower", F("email", WithTable("u")))
+//	Fn("round", F("price", WithTable("p")), 2)
+func Fn(name string, args ...any) Func {
+	return Func{
+		Name: name,
+		Args: args,
+	}
+}
+
+// Count creates a COUNT aggregate. Without arguments it counts rows with COUNT(*).
+//
+// Examples:
+//
+//	Count()                         // COUNT(*)
+//	Count(F("id", WithTable("o")))  // COUNT("o"."id")
+func Count(args ...any) Func {
+	return Fn("COUNT", args...)
+}
+
+// CountDistinct creates a COUNT(DISTINCT ...) aggregate.
+func CountDistinct(arg any) Func {
+	return Func{
+		Name:     "COUNT",
+		Args:     []any{arg},
+		Distinct: true,
+	}
+}
+
+// Sum creates a SUM aggregate.
+func Sum(arg any) Func {
+	return Fn("SUM", arg)
+}
+
+// Avg creates an AVG aggregate.
+func Avg(arg any) Func {
+	return Fn("AVG", arg)
+}
+
+// Min creates a MIN aggregate.
+func Min(arg any) Func {
+	return Fn("MIN", arg)
+}
+
+// Max creates a MAX aggregate.
+func Max(arg any) Func {
+	return Fn("MAX", arg)
+}
@@ -0,0 +1,262 @@
+package supersaiyan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const (
+	// JSONContainsOp matches when the left JSON document contains the value (@> on
+	// PostgreSQL JSONB, JSON_CONTAINS on MySQL). String values are bound as JSON text,
+	// anything else is marshaled to JSON first.
+	JSONContainsOp exp.BooleanOperation = iota + 100
+	// JSONHasKeyOp matches when the left JSON object has the top-level key given as value.
+	JSONHasKeyOp
+)
+
+// JSONPath represents the extraction of a value from a JSON column by a path of object keys
+// (strings) and array indexes (ints). Text extracts the value as text (->> on PostgreSQL)
+// instead of JSON, which is what comparisons against plain values need.
+type JSONPath struct {
+	Exp  any   `json:"json"           yaml:"json"`
+	Path []any `json:"path"           yaml:"path"`
+	Text bool  `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+// jsonKeyPattern matches object keys that need no quoting in a JSON path.
+var jsonKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expression converts the JSONPath to a goqu literal expression for the query's dialect.
+// On PostgreSQL object keys are bound as parameters and array indexes are inlined; the other
+// dialects bind the whole path as one SQL/JSON path string.
+func (j JSONPath) expression(rc *renderContext) exp.LiteralExpression {
+	value := handleAny(rc, j.Exp)
+	if len(j.Path) == 0 {
+		rc.fail(fmt.Errorf("%w: JSON path is empty", ErrInvalidExpression))
+		return goqu.L("NULL")
+	}
+
+	if rc.dialect == "postgres" || rc.dialect == "" {
+		var sb strings.Builder
+		args := make([]any, 0, len(j.Path)+1)
+		sb.WriteString("(?")
+		args = append(args, value)
+		for i, elem := range j.Path {
+			key, ok := jsonPathElement(elem)
+			if !ok {
+				return j.invalidElement(rc, elem)
+			}
+			operator := " -> "
+			if j.Text && i == len(j.Path)-1 {
+				operator = " ->> "
+			}
+			// array indexes are inlined: a bound parameter would be looked up as a text key
+			if index, isIndex := key.(int); isIndex {
+				sb.WriteString(operator + strconv.Itoa(index))
+				continue
+			}
+			sb.WriteString(operator + "?")
+			args = append(args, key)
+		}
+		sb.WriteByte(')')
+		return goqu.L(sb.String(), args...)
+	}
+
+	path, err := jsonPathString(j.Path)
+	if err != nil {
+		rc.fail(err)
+		return goqu.L("NULL")
+	}
+
+	switch rc.dialect {
+	case "mysql":
+		if j.Text {
+			return goqu.L("JSON_UNQUOTE(JSON_EXTRACT(?, ?))", value, path)
+		}
+		return goqu.L("JSON_EXTRACT(?, ?)", value, path)
+	case "sqlserver":
+		if j.Text {
+			return goqu.L("JSON_VALUE(?, ?)", value, path)
+		}
+		return goqu.L("JSON_QUERY(?, ?)", value, path)
+	default:
+		// SQLite's JSON_EXTRACT already returns scalars as SQL values
+		return goqu.L("JSON_EXTRACT(?, ?)", value, path)
+	}
+}
+
+// invalidElement records an invalid path element error and returns a placeholder expression.
+func (j JSONPath) invalidElement(rc *renderContext, elem any) exp.LiteralExpression {
+	rc.fail(fmt.Errorf("%w: invalid JSON path element %v", ErrInvalidExpression, elem))
+	return goqu.L("NULL")
+}
+
+// jsonPathElement normalizes a path element to a string key or an int index.
+// Whole float64 values, as decoded from documents, are indexes.
+func jsonPathElement(elem any) (any, bool) {
+	switch e := elem.(type) {
+	case string:
+		return e, true
+	case int:
+		return e, e >= 0
+	case float64:
+		if e >= 0 && e == float64(int(e)) {
+			return int(e), true
+		}
+	}
+	return nil, false
+}
+
+// jsonKeyEscaper escapes backslashes and double quotes in quoted path keys.
+var jsonKeyEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// jsonPathString builds a SQL/JSON path such as $.address."zip code"[0].
+func jsonPathString(path []any) (string, error) {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, elem := range path {
+		key, ok := jsonPathElement(elem)
+		if !ok {
+			return "", fmt.Errorf("%w: invalid JSON path element %v", ErrInvalidExpression, elem)
+		}
+		switch k := key.(type) {
+		case int:
+			sb.WriteString("[" + strconv.Itoa(k) + "]")
+		case string:
+			if jsonKeyPattern.MatchString(k) {
+				sb.WriteString("." + k)
+			} else {
+				sb.WriteString(`."` + jsonKeyEscaper.Replace(k) + `"`)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// jsonExpression renders the JSON containment and key existence operators.
+func (bo BoolOp) jsonExpression(rc *renderContext, left comparableExpression) exp.Expression {
+	switch bo.Op {
+	case JSONContainsOp:
+		doc, err := jsonDocument(bo.Value)
+		if err != nil {
+			rc.fail(fmt.Errorf("%w: %w", ErrInvalidCondition, err))
+			return nil
+		}
+		switch rc.dialect {
+		case "postgres", "":
+			return goqu.L("(? @> ?::jsonb)", left, doc)
+		case "mysql":
+			return goqu.L("JSON_CONTAINS(?, ?)", left, doc)
+		}
+
+	case JSONHasKeyOp:
+		key, ok := bo.Value.(string)
+		if !ok {
+			rc.fail(fmt.Errorf("%w: JSON key must be a string", ErrInvalidCondition))
+			return nil
+		}
+		path, _ := jsonPathString([]any{key})
+		switch rc.dialect {
+		case "postgres", "":
+			// jsonb_exists is the function behind the ? operator, which clashes with placeholders
+			return goqu.L("JSONB_EXISTS(?, ?)", left, key)
+		case "mysql":
+			return goqu.L("JSON_CONTAINS_PATH(?, 'one', ?)", left, path)
+		case "sqlite3":
+			return goqu.L("(JSON_TYPE(?, ?) IS NOT NULL)", left, path)
+		case "sqlserver":
+			return goqu.L("(JSON_PATH_EXISTS(?, ?) = 1)", left, path)
+		}
+	}
+
+	rc.fail(fmt.Errorf("%w: %s on %q", ErrUnsupportedByDialect, boolOpToString(bo.Op), rc.dialect))
+	return nil
+}
+
+// jsonDocument returns the JSON text bound for a containment value.
+func jsonDocument(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for JSONPath.
+func (j *JSONPath) UnmarshalJSON(data []byte) error {
+	aux := &struct {
+		Exp  json.RawMessage `json:"json"`
+		Path []any           `json:"path"`
+		Text bool            `json:"text,omitempty"`
+	}{}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	j.Path = aux.Path
+	j.Text = aux.Text
+	if len(aux.Exp) > 0 {
+		value, err := unmarshalValue(aux.Exp)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal JSON column: %w", err)
+		}
+		j.Exp = value
+	}
+
+	return nil
+}
+
+// JSONGet extracts a JSON value from a JSON column.
+//
+// Examples:
+//
+//	JSONGet(F("attrs", WithTable("u")), "tags", 0) // ("u"."attrs" -> ? -> 0) on postgres
+func JSONGet(value any, path ...any) JSONPath {
+	return JSONPath{Exp: value, Path: path}
+}
+
+// JSONText extracts a value from a JSON column as text, for comparisons with plain values.
+//
+// Examples:
+//
+//	JSONText(F("attrs", WithTable("u")), "address", "city")
+//	// postgres:  ("u"."attrs" -> ? ->> ?)
+//	// mysql:     JSON_UNQUOTE(JSON_EXTRACT("u"."attrs", ?)) bound to $.address.city
+//	// sqlserver: JSON_VALUE("u"."attrs", ?)
+func JSONText(value any, path ...any) JSONPath {
+	return JSONPath{Exp: value, Path: path, Text: true}
+}
+
+// JSONContains creates a JSON containment comparison.
+//
+// Examples:
+//
+//	JSONContains("attrs", "u", map[string]any{"role": "admin"}) // ("u"."attrs" @> ?::jsonb)
+func JSONContains(fieldName, tableAlias string, value any) BoolOp {
+	return BoolOp{
+		Op:         JSONContainsOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      value,
+	}
+}
+
+// JSONHasKey creates a check that a JSON object has a top-level key.
+func JSONHasKey(fieldName, tableAlias, key string) BoolOp {
+	return BoolOp{
+		Op:         JSONHasKeyOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      key,
+	}
+}
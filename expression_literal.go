@@ -16,10 +16,10 @@ type Literal struct {
 }
 
 // expression converts the Literal to a goqu literal expression.
-func (l Literal) expression() exp.LiteralExpression {
+func (l Literal) expression(rc *renderContext) exp.LiteralExpression {
 	argContainer := make([]any, len(l.Args))
 	for i, arg := range l.Args {
-		argContainer[i] = handleAny(arg)
+		argContainer[i] = handleAny(rc, arg)
 	}
 
 	return goqu.L(l.Value, argContainer...)
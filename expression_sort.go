@@ -2,27 +2,113 @@ package supersaiyan
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 )
 
-// Sort represents an ORDER BY clause with column name, table alias, and direction.
+// Sort represents an ORDER BY clause with a sort target, direction, and optional NULLS placement.
+// The target is, in order of precedence, an expression (Exp), a selected field alias (FieldAlias),
+// or a column reference (Name with optional TableAlias).
 type Sort struct {
-	Name       string            `json:"name"                 yaml:"name"`
+	Name       string            `json:"name,omitempty"       yaml:"name,omitempty"`
 	TableAlias string            `json:"tableAlias,omitempty" yaml:"tableAlias,omitempty"`
+	FieldAlias string            `json:"fieldAlias,omitempty" yaml:"fieldAlias,omitempty"`
+	Exp        any               `json:"exp,omitempty"        yaml:"exp,omitempty"`
 	Order      exp.SortDirection `json:"order"                yaml:"order"`
+	Nulls      exp.NullSortType  `json:"nulls,omitempty"      yaml:"nulls,omitempty"`
 }
 
-// expression converts the Sort to a goqu ordered expression.
-func (s Sort) expression() exp.OrderedExpression {
-	switch s.Order {
-	case exp.DescSortDir:
-		return goqu.C(s.Name).Table(s.TableAlias).Desc()
+// target returns the expression being sorted on.
+func (s Sort) target(rc *renderContext) exp.Expression {
+	switch {
+	case s.Exp != nil:
+		return handleAny(rc, s.Exp)
+	case s.FieldAlias != "":
+		return goqu.I(s.FieldAlias)
 	default:
-		return goqu.C(s.Name).Table(s.TableAlias).Asc()
+		return goqu.C(s.Name).Table(s.TableAlias)
+	}
+}
+
+// expressions converts the Sort to goqu ordered expressions for the query's dialect.
+// MySQL and SQL Server have no NULLS FIRST/LAST syntax, so the placement is emulated
+// with a leading CASE sort key that orders NULL rows before or after the rest. Select
+// aliases cannot be used inside that key, so an alias sort is emulated on the expression
+// of the selected field it names.
+func (s Sort) expressions(rc *renderContext, fields []Field) []exp.OrderedExpression {
+	target := s.target(rc)
+	nulls := s.nullSortType()
+
+	if nulls != exp.NoNullsSortType && !capabilitiesOf(rc.dialect).NullsOrdering {
+		nullsFirst, nullsLast := 0, 1
+		if nulls == exp.NullsLastSortType {
+			nullsFirst, nullsLast = 1, 0
+		}
+		isNull := exp.NewBooleanExpression(exp.IsOp, s.nullTarget(rc, fields, target), nil)
+		nullKey := goqu.Case().When(isNull, nullsFirst).Else(nullsLast)
+
+		return []exp.OrderedExpression{
+			nullKey.Asc(),
+			exp.NewOrderedExpression(target, s.Order, exp.NoNullsSortType),
+		}
 	}
+
+	return []exp.OrderedExpression{exp.NewOrderedExpression(target, s.Order, nulls)}
+}
+
+// nullTarget returns the expression the emulated NULLS placement tests: the target itself,
+// or for an alias sort the unaliased expression of the selected field with that alias.
+func (s Sort) nullTarget(rc *renderContext, fields []Field, target exp.Expression) exp.Expression {
+	if s.Exp != nil || s.FieldAlias == "" {
+		return target
+	}
+
+	for _, f := range fields {
+		alias := f.FieldAlias
+		if alias == "" && f.Exp != nil {
+			alias = f.Name
+		}
+		if alias != s.FieldAlias {
+			continue
+		}
+		if f.Exp != nil {
+			return handleAny(rc, f.Exp)
+		}
+		return f.identifierExpression()
+	}
+
+	rc.fail(fmt.Errorf(
+		"%w: NULLS placement on alias %q that is not selected, on %q",
+		ErrUnsupportedByDialect,
+		s.FieldAlias,
+		rc.dialect,
+	))
+	return target
+}
+
+// nullSortType returns the NULLS placement, treating unset values as no placement.
+func (s Sort) nullSortType() exp.NullSortType {
+	switch s.Nulls {
+	case exp.NullsFirstSortType, exp.NullsLastSortType:
+		return s.Nulls
+	default:
+		return exp.NoNullsSortType
+	}
+}
+
+// NullsFirst returns a copy of the Sort that places NULL values first.
+func (s Sort) NullsFirst() Sort {
+	s.Nulls = exp.NullsFirstSortType
+	return s
+}
+
+// NullsLast returns a copy of the Sort that places NULL values last.
+func (s Sort) NullsLast() Sort {
+	s.Nulls = exp.NullsLastSortType
+	return s
 }
 
 // ParseSortDirection converts a string to a goqu SortDirection.
@@ -39,22 +125,31 @@ func ParseSortDirection(s string) exp.SortDirection {
 // MarshalJSON implements custom JSON marshaling for Sort.
 func (s Sort) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		Name       string `json:"name"`
+		Name       string `json:"name,omitempty"`
 		TableAlias string `json:"tableAlias,omitempty"`
+		FieldAlias string `json:"fieldAlias,omitempty"`
+		Exp        any    `json:"exp,omitempty"`
 		Order      string `json:"order"`
+		Nulls      string `json:"nulls,omitempty"`
 	}{
 		Name:       s.Name,
 		TableAlias: s.TableAlias,
+		FieldAlias: s.FieldAlias,
+		Exp:        s.Exp,
 		Order:      sortDirectionToString(s.Order),
+		Nulls:      nullSortTypeToString(s.Nulls),
 	})
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Sort.
 func (s *Sort) UnmarshalJSON(data []byte) error {
 	aux := &struct {
-		Name       string `json:"name"`
-		TableAlias string `json:"tableAlias,omitempty"`
-		Order      string `json:"order"`
+		Name       string          `json:"name"`
+		TableAlias string          `json:"tableAlias,omitempty"`
+		FieldAlias string          `json:"fieldAlias,omitempty"`
+		Exp        json.RawMessage `json:"exp,omitempty"`
+		Order      string          `json:"order"`
+		Nulls      string          `json:"nulls,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
@@ -63,7 +158,18 @@ func (s *Sort) UnmarshalJSON(data []byte) error {
 
 	s.Name = aux.Name
 	s.TableAlias = aux.TableAlias
+	s.FieldAlias = aux.FieldAlias
 	s.Order = stringToSortDirection(aux.Order)
+	s.Nulls = stringToNullSortType(aux.Nulls)
+
+	// Unmarshal Exp with type detection
+	if len(aux.Exp) > 0 {
+		exp, err := unmarshalExpression(aux.Exp)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal sort expression: %w", err)
+		}
+		s.Exp = exp
+	}
 
 	return nil
 }
@@ -71,22 +177,31 @@ func (s *Sort) UnmarshalJSON(data []byte) error {
 // MarshalYAML implements custom YAML marshaling for Sort.
 func (s Sort) MarshalYAML() (interface{}, error) {
 	return &struct {
-		Name       string `yaml:"name"`
+		Name       string `yaml:"name,omitempty"`
 		TableAlias string `yaml:"tableAlias,omitempty"`
+		FieldAlias string `yaml:"fieldAlias,omitempty"`
+		Exp        any    `yaml:"exp,omitempty"`
 		Order      string `yaml:"order"`
+		Nulls      string `yaml:"nulls,omitempty"`
 	}{
 		Name:       s.Name,
 		TableAlias: s.TableAlias,
+		FieldAlias: s.FieldAlias,
+		Exp:        s.Exp,
 		Order:      sortDirectionToString(s.Order),
+		Nulls:      nullSortTypeToString(s.Nulls),
 	}, nil
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling for Sort.
 func (s *Sort) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	aux := &struct {
-		Name       string `yaml:"name"`
-		TableAlias string `yaml:"tableAlias,omitempty"`
-		Order      string `yaml:"order"`
+		Name       string                 `yaml:"name"`
+		TableAlias string                 `yaml:"tableAlias,omitempty"`
+		FieldAlias string                 `yaml:"fieldAlias,omitempty"`
+		Exp        map[string]interface{} `yaml:"exp,omitempty"`
+		Order      string                 `yaml:"order"`
+		Nulls      string                 `yaml:"nulls,omitempty"`
 	}{}
 
 	if err := unmarshal(&aux); err != nil {
@@ -95,7 +210,24 @@ func (s *Sort) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	s.Name = aux.Name
 	s.TableAlias = aux.TableAlias
+	s.FieldAlias = aux.FieldAlias
 	s.Order = stringToSortDirection(aux.Order)
+	s.Nulls = stringToNullSortType(aux.Nulls)
+
+	// Unmarshal Exp with type detection
+	if len(aux.Exp) > 0 {
+		// Convert map to JSON and then unmarshal using our JSON logic
+		jsonData, err := json.Marshal(aux.Exp)
+		if err != nil {
+			return fmt.Errorf("failed to marshal exp to JSON: %w", err)
+		}
+
+		exp, err := unmarshalExpression(jsonData)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal sort expression: %w", err)
+		}
+		s.Exp = exp
+	}
 
 	return nil
 }
@@ -122,6 +254,28 @@ func stringToSortDirection(s string) exp.SortDirection {
 	}
 }
 
+func nullSortTypeToString(nst exp.NullSortType) string {
+	switch nst {
+	case exp.NullsFirstSortType:
+		return "FIRST"
+	case exp.NullsLastSortType:
+		return "LAST"
+	default:
+		return ""
+	}
+}
+
+func stringToNullSortType(s string) exp.NullSortType {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "FIRST":
+		return exp.NullsFirstSortType
+	case "LAST":
+		return exp.NullsLastSortType
+	default:
+		return exp.NoNullsSortType
+	}
+}
+
 // Asc creates an ascending sort direction.
 func Asc(name, tableAlias string) Sort {
 	return Sort{
@@ -139,3 +293,44 @@ func Desc(name, tableAlias string) Sort {
 		Order:      exp.DescSortDir,
 	}
 }
+
+// AscExp creates an ascending sort on an expression such as a Case, Coalesce, or Literal.
+//
+// Examples:
+//
+//	AscExp(Coal(nil, F("nickname", WithTable("u")), F("username", WithTable("u"))))
+//	AscExp(L("RANDOM()"))
+func AscExp(expression any) Sort {
+	return Sort{
+		Exp:   expression,
+		Order: exp.AscDir,
+	}
+}
+
+// DescExp creates a descending sort on an expression such as a Case, Coalesce, or Literal.
+func DescExp(expression any) Sort {
+	return Sort{
+		Exp:   expression,
+		Order: exp.DescSortDir,
+	}
+}
+
+// AscAlias creates an ascending sort on a selected field alias.
+//
+// Examples:
+//
+//	AscAlias("order_count")
+func AscAlias(fieldAlias string) Sort {
+	return Sort{
+		FieldAlias: fieldAlias,
+		Order:      exp.AscDir,
+	}
+}
+
+// DescAlias creates a descending sort on a selected field alias.
+func DescAlias(fieldAlias string) Sort {
+	return Sort{
+		FieldAlias: fieldAlias,
+		Order:      exp.DescSortDir,
+	}
+}
@@ -3,12 +3,17 @@ package supersaiyan
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 )
 
-// WhereGroup represents a group of WHERE conditions combined with AND or OR.
+// NotType is the WhereGroup operator that negates the AND of its conditions.
+// It is not a goqu list type and is rendered as NOT (...) by the builder.
+const NotType exp.ExpressionListType = -1
+
+// WhereGroup represents a group of WHERE conditions combined with AND or OR, or negated with NOT.
 type WhereGroup struct {
 	Op         exp.ExpressionListType `json:"op"         yaml:"op"`
 	Conditions []any                  `json:"conditions" yaml:"conditions"`
@@ -16,22 +21,11 @@ type WhereGroup struct {
 
 // expression converts the WhereGroup to a goqu expression.
 // It recursively handles nested groups and combines conditions with the specified operator.
-func (wg WhereGroup) expression() exp.Expression {
+func (wg WhereGroup) expression(rc *renderContext) exp.Expression {
 	exps := make([]exp.Expression, 0, len(wg.Conditions))
 
 	for _, cond := range wg.Conditions {
-		var expr exp.Expression
-
-		switch v := cond.(type) {
-		case BoolOp:
-			expr = v.expression()
-		case RangeOp:
-			expr = v.expression()
-		case WhereGroup:
-			expr = v.expression()
-		}
-
-		if expr != nil {
+		if expr := conditionExpression(rc, cond); expr != nil {
 			exps = append(exps, expr)
 		}
 	}
@@ -44,11 +38,33 @@ func (wg WhereGroup) expression() exp.Expression {
 	switch wg.Op {
 	case exp.OrType:
 		return goqu.Or(exps...)
+	case NotType:
+		return goqu.L("(NOT ?)", goqu.And(exps...))
 	default:
 		return goqu.And(exps...)
 	}
 }
 
+// conditionExpression converts a WHERE condition to a goqu expression. Besides conditions,
+// literals, functions, case expressions, boolean columns, subqueries and goqu expressions are
+// accepted; any other value fails the query rather than being dropped. Empty groups render
+// nothing and return nil.
+func conditionExpression(rc *renderContext, cond any) exp.Expression {
+	if r := reflect.ValueOf(cond); r.Kind() == reflect.Ptr && !r.IsNil() {
+		cond = r.Elem().Interface()
+	}
+
+	switch v := cond.(type) {
+	case Condition:
+		return v.toExpression(rc)
+	case Literal, Func, Case, Field, JSONPath, Cast, Coalesce, SQLBuilder, exp.Expression:
+		return handleAny(rc, v)
+	default:
+		rc.fail(fmt.Errorf("%w: %T cannot be used as a condition", ErrInvalidCondition, cond))
+		return nil
+	}
+}
+
 // MarshalJSON implements custom JSON marshaling for WhereGroup.
 func (wg WhereGroup) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
@@ -94,6 +110,8 @@ func expressionListTypeToString(elt exp.ExpressionListType) string {
 		return "AND"
 	case exp.OrType:
 		return "OR"
+	case NotType:
+		return "NOT"
 	default:
 		return "AND"
 	}
@@ -105,6 +123,8 @@ func stringToExpressionListType(s string) exp.ExpressionListType {
 		return exp.OrType
 	case "AND":
 		return exp.AndType
+	case "NOT":
+		return NotType
 	default:
 		return exp.AndType
 	}
@@ -125,3 +145,17 @@ func Or(conditions ...any) WhereGroup {
 		Conditions: conditions,
 	}
 }
+
+// Not creates a NOT group that negates the given conditions.
+// Multiple conditions are combined with AND before being negated.
+//
+// Examples:
+//
+//	Not(Eq("status", "u", "banned"))
+//	Not(Or(Eq("role", "u", "guest"), IsNull("email", "u")))
+func Not(conditions ...any) WhereGroup {
+	return WhereGroup{
+		Op:         NotType,
+		Conditions: conditions,
+	}
+}
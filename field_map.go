@@ -0,0 +1,81 @@
+package supersaiyan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownField is returned when an input references a field that is not declared in a FieldMap.
+var ErrUnknownField = errors.New("unknown field")
+
+// FieldResolver resolves the public field names of parsed input to Fields, acting as the
+// allow-list of the parsers. It is implemented by FieldMap and by AllowAnyField; a nil
+// FieldResolver rejects every name.
+type FieldResolver interface {
+	// resolve returns the Field for a public name, or ErrUnknownField.
+	resolve(name string) (Field, error)
+}
+
+// FieldMap maps public field names used by API clients to the Fields they refer to.
+// It doubles as an allow-list: names that are not in the map are rejected, and a nil
+// FieldMap rejects every name. Fields with an Exp are filtered and sorted on that expression.
+// Use AllowAnyField to accept any name instead.
+type FieldMap map[string]Field
+
+// resolve returns the Field registered under the given public name.
+func (fm FieldMap) resolve(name string) (Field, error) {
+	f, ok := fm[name]
+	if !ok {
+		return Field{}, fmt.Errorf("%w: %q", ErrUnknownField, name)
+	}
+	return f, nil
+}
+
+// anyField is the FieldResolver accepting every name.
+type anyField struct{}
+
+// resolve reads the name as a column path.
+func (anyField) resolve(name string) (Field, error) {
+	return fieldFromPath(name), nil
+}
+
+// lookupField resolves name through fields, rejecting it when fields is nil.
+func lookupField(fields FieldResolver, name string) (Field, error) {
+	if fields == nil {
+		return Field{}, fmt.Errorf("%w: %q", ErrUnknownField, name)
+	}
+	return fields.resolve(name)
+}
+
+// fieldFromPath converts "column" or "alias.column" into a Field.
+func fieldFromPath(path string) Field {
+	if alias, name, ok := strings.Cut(path, "."); ok {
+		return Field{Name: name, TableAlias: alias}
+	}
+	return Field{Name: path}
+}
+
+// AllowAnyField returns a FieldResolver that accepts any name, reading "alias.column" as a
+// table-qualified column. It suits trusted input only: clients can reach every column.
+//
+// Examples:
+//
+//	ParseSorts("-created_at,u.username", AllowAnyField())
+func AllowAnyField() FieldResolver {
+	return anyField{}
+}
+
+// AllowFields creates a FieldMap that exposes the given columns of one table under their own names.
+//
+// Examples:
+//
+//	AllowFields("u", "id", "username", "created_at")
+//	AllowFields("", "id", "name")
+func AllowFields(tableAlias string, names ...string) FieldMap {
+	fm := make(FieldMap, len(names))
+	for _, name := range names {
+		fm[name] = Field{Name: name, TableAlias: tableAlias}
+	}
+	return fm
+}
@@ -2,11 +2,55 @@ package supersaiyan
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 )
 
+// renderContext carries per-query state through expression rendering: the target dialect,
+// the clock relative times resolve against, the named relations joins can refer to, the
+// table aliases in scope and the first error met, which the builder reports from ToSQL.
+type renderContext struct {
+	dialect   string
+	clock     func() time.Time
+	relations RelationMap
+	aliases   map[string]bool
+	nowTime   time.Time
+	err       error
+}
+
+// now returns the clock's time, read once so every relative time in a query agrees.
+func (rc *renderContext) now() time.Time {
+	if rc.nowTime.IsZero() {
+		if rc.clock != nil {
+			rc.nowTime = rc.clock()
+		} else {
+			rc.nowTime = time.Now()
+		}
+	}
+	return rc.nowTime
+}
+
+// inherit passes the query's clock and relations to a subquery that has none of its own,
+// along with the aliases in scope.
+func (rc *renderContext) inherit(qb *SQLBuilder) {
+	if qb.clock == nil {
+		qb.clock = rc.now
+	}
+	if qb.relationMap == nil {
+		qb.relationMap = rc.relations
+	}
+	qb.outerAliases = rc.aliases
+}
+
+// fail records err unless an earlier error was already recorded.
+func (rc *renderContext) fail(err error) {
+	if rc.err == nil {
+		rc.err = err
+	}
+}
+
 // handleAnyOptions contains options for converting arbitrary values to goqu expressions.
 type handleAnyOptions struct {
 	alias string
@@ -23,9 +67,10 @@ func withAlias(alias string) handleAnyOption {
 }
 
 // handleAny recursively converts arbitrary values to goqu expressions.
-// It supports SQLBuilder, Field, BoolOp, WhereGroup, RangeOp, Literal, Case, Coalesce,
-// goqu.Expression, slices, and primitive values.
-func handleAny(a any, opts ...handleAnyOption) exp.Expression {
+// It supports SQLBuilder, Field, BoolOp, WhereGroup, RangeOp, Search, SearchRank, Literal, Case,
+// Func, Arith, Cast, DateFunc, RelativeTime, JSONPath, Coalesce, goqu.Expression, slices, and
+// primitive values.
+func handleAny(rc *renderContext, a any, opts ...handleAnyOption) exp.Expression {
 	// Handle nil values explicitly
 	if a == nil {
 		return goqu.L("NULL")
@@ -48,51 +93,115 @@ func handleAny(a any, opts ...handleAnyOption) exp.Expression {
 
 	// Handle SQLBuilder (subquery)
 	if qb, ok := a.(SQLBuilder); ok {
+		rc.inherit(&qb)
 		return qb.mainSelect()
 	}
 
 	// Handle Field
 	if f, ok := a.(Field); ok {
-		return f.expression()
+		return f.expression(rc)
 	}
 
 	// Handle BoolOp
 	if bo, ok := a.(BoolOp); ok {
-		return bo.expression()
+		return bo.expression(rc)
 	}
 
 	// Handle WhereGroup
 	if wg, ok := a.(WhereGroup); ok {
-		return wg.expression()
+		return wg.expression(rc)
 	}
 
 	// Handle RangeOp
 	if ro, ok := a.(RangeOp); ok {
-		return ro.expression()
+		return ro.expression(rc)
+	}
+
+	// Handle Search
+	if s, ok := a.(Search); ok {
+		return s.expression(rc)
+	}
+
+	// Handle SearchRank
+	if sr, ok := a.(SearchRank); ok {
+		if options.alias != "" {
+			return sr.expression(rc).As(options.alias)
+		}
+		return sr.expression(rc)
 	}
 
 	// Handle Literal
 	if l, ok := a.(Literal); ok {
 		if options.alias != "" {
-			return l.expression().As(options.alias)
+			return l.expression(rc).As(options.alias)
 		}
-		return l.expression()
+		return l.expression(rc)
 	}
 
 	// Handle Case
 	if c, ok := a.(Case); ok {
 		if options.alias != "" {
-			return c.expression().As(options.alias)
+			return c.expression(rc).As(options.alias)
 		}
-		return c.expression()
+		return c.expression(rc)
+	}
+
+	// Handle Func
+	if fn, ok := a.(Func); ok {
+		if options.alias != "" {
+			return fn.expression(rc).As(options.alias)
+		}
+		return fn.expression(rc)
+	}
+
+	// Handle Arith
+	if ar, ok := a.(Arith); ok {
+		if options.alias != "" {
+			return ar.expression(rc).As(options.alias)
+		}
+		return ar.expression(rc)
+	}
+
+	// Handle Cast
+	if ca, ok := a.(Cast); ok {
+		if options.alias != "" {
+			return ca.expression(rc).As(options.alias)
+		}
+		return ca.expression(rc)
+	}
+
+	// Handle DateFunc
+	if df, ok := a.(DateFunc); ok {
+		if options.alias != "" {
+			return df.expression(rc).As(options.alias)
+		}
+		return df.expression(rc)
+	}
+
+	// Handle RelativeTime, bound as the time it resolves to
+	if rt, ok := a.(RelativeTime); ok {
+		t, err := rt.Resolve(rc.now())
+		if err != nil {
+			rc.fail(err)
+			return goqu.L("NULL")
+		}
+		return goqu.V(t)
+	}
+
+	// Handle JSONPath
+	if jp, ok := a.(JSONPath); ok {
+		if options.alias != "" {
+			return jp.expression(rc).As(options.alias)
+		}
+		return jp.expression(rc)
 	}
 
 	// Handle Coalesce
 	if co, ok := a.(Coalesce); ok {
 		if options.alias != "" {
-			return co.expression().As(options.alias)
+			return co.expression(rc).As(options.alias)
 		}
-		return co.expression()
+		return co.expression(rc)
 	}
 
 	// Handle goqu.Expression directly
@@ -115,3 +224,44 @@ func handleAny(a any, opts ...handleAnyOption) exp.Expression {
 	// Default: treat as a literal value
 	return goqu.V(a)
 }
+
+// comparableExpression is a goqu expression that can be the left operand of a BoolOp or RangeOp.
+type comparableExpression interface {
+	exp.Comparable
+	exp.Inable
+	exp.Isable
+	exp.Likeable
+	exp.Rangeable
+}
+
+// leftOperand resolves the left operand of a comparison. A nil left falls back to the
+// fieldName/tableAlias column. Field aliases are dropped since they are not valid there.
+func leftOperand(rc *renderContext, left any, fieldName, tableAlias string) comparableExpression {
+	switch l := left.(type) {
+	case nil:
+		return goqu.C(fieldName).Table(tableAlias)
+	case Field:
+		if l.Exp == nil {
+			return l.identifierExpression()
+		}
+		return leftOperand(rc, l.Exp, "", "")
+	case Literal:
+		return l.expression(rc)
+	case Func:
+		return l.expression(rc)
+	case Arith:
+		return l.expression(rc)
+	case Cast:
+		return l.expression(rc)
+	case DateFunc:
+		return l.expression(rc)
+	case JSONPath:
+		return l.expression(rc)
+	case SearchRank:
+		return l.expression(rc)
+	case comparableExpression:
+		return l
+	default:
+		return goqu.L("?", handleAny(rc, l))
+	}
+}
@@ -31,9 +31,9 @@ func unmarshalCondition(data []byte) (any, error) {
 		return nil, err
 	}
 
-	// Check for BoolOp (has "op" and "fieldName")
+	// Check for BoolOp (has "op" and "fieldName" or "left")
 	if _, hasOp := typeDetector["op"]; hasOp {
-		if _, hasFieldName := typeDetector["fieldName"]; hasFieldName {
+		if hasLeftOperand(typeDetector) {
 			// Check if it's a RangeOp (has "start" and "end")
 			if _, hasStart := typeDetector["start"]; hasStart {
 				var rangeOp RangeOp
@@ -59,6 +59,20 @@ func unmarshalCondition(data []byte) (any, error) {
 		}
 	}
 
+	// Check for Search (has "search")
+	if _, hasSearch := typeDetector["search"]; hasSearch {
+		var search Search
+		if err := json.Unmarshal(data, &search); err != nil {
+			return nil, err
+		}
+		return search, nil
+	}
+
+	// Check for a MongoDB-style filter document
+	if isMongoFilter(typeDetector) {
+		return parseMongoDocument(data, AllowAnyField())
+	}
+
 	return nil, fmt.Errorf("unknown condition type")
 }
 
@@ -70,6 +84,76 @@ func unmarshalExpression(data []byte) (any, error) {
 		return nil, err
 	}
 
+	// Check for Func (has "func")
+	if _, hasFunc := typeDetector["func"]; hasFunc {
+		var fn Func
+		if err := json.Unmarshal(data, &fn); err != nil {
+			return nil, err
+		}
+		return fn, nil
+	}
+
+	// Check for Arith (has "arith")
+	if _, hasArith := typeDetector["arith"]; hasArith {
+		var arith Arith
+		if err := json.Unmarshal(data, &arith); err != nil {
+			return nil, err
+		}
+		return arith, nil
+	}
+
+	// Check for Cast (has "cast")
+	if _, hasCast := typeDetector["cast"]; hasCast {
+		var cast Cast
+		if err := json.Unmarshal(data, &cast); err != nil {
+			return nil, err
+		}
+		return cast, nil
+	}
+
+	// Check for DateFunc (has "date")
+	if _, hasDate := typeDetector["date"]; hasDate {
+		var dateFunc DateFunc
+		if err := json.Unmarshal(data, &dateFunc); err != nil {
+			return nil, err
+		}
+		return dateFunc, nil
+	}
+
+	// Check for RelativeTime (has "relative")
+	if _, hasRelative := typeDetector["relative"]; hasRelative {
+		var relative RelativeTime
+		if err := json.Unmarshal(data, &relative); err != nil {
+			return nil, err
+		}
+		return relative, nil
+	}
+
+	// Check for JSONPath (has "json")
+	if _, hasJSON := typeDetector["json"]; hasJSON {
+		var jsonPath JSONPath
+		if err := json.Unmarshal(data, &jsonPath); err != nil {
+			return nil, err
+		}
+		return jsonPath, nil
+	}
+
+	// Check for Search (has "search") and SearchRank (has "rank")
+	if _, hasSearch := typeDetector["search"]; hasSearch {
+		var search Search
+		if err := json.Unmarshal(data, &search); err != nil {
+			return nil, err
+		}
+		return search, nil
+	}
+	if _, hasRank := typeDetector["rank"]; hasRank {
+		var rank SearchRank
+		if err := json.Unmarshal(data, &rank); err != nil {
+			return nil, err
+		}
+		return rank, nil
+	}
+
 	// Check for Case (has "conditions" array with "when"/"then")
 	if conditionsRaw, hasConditions := typeDetector["conditions"]; hasConditions {
 		var testConditions []map[string]any
@@ -85,6 +169,15 @@ func unmarshalExpression(data []byte) (any, error) {
 		}
 	}
 
+	// Check for a subquery (has "table")
+	if _, hasTable := typeDetector["table"]; hasTable {
+		var subquery SQLBuilder
+		if err := json.Unmarshal(data, &subquery); err != nil {
+			return nil, err
+		}
+		return subquery, nil
+	}
+
 	// Check for Coalesce (has "fields" array)
 	if _, hasFields := typeDetector["fields"]; hasFields {
 		var coalesce Coalesce
@@ -114,7 +207,7 @@ func unmarshalExpression(data []byte) (any, error) {
 
 	// Check for BoolOp
 	if _, hasOp := typeDetector["op"]; hasOp {
-		if _, hasFieldName := typeDetector["fieldName"]; hasFieldName {
+		if hasLeftOperand(typeDetector) {
 			// Check if it's a RangeOp
 			if _, hasStart := typeDetector["start"]; hasStart {
 				var rangeOp RangeOp
@@ -135,6 +228,13 @@ func unmarshalExpression(data []byte) (any, error) {
 	return nil, fmt.Errorf("unknown expression type")
 }
 
+// hasLeftOperand reports whether a document names the left operand of a comparison.
+func hasLeftOperand(typeDetector map[string]json.RawMessage) bool {
+	_, hasFieldName := typeDetector["fieldName"]
+	_, hasLeft := typeDetector["left"]
+	return hasFieldName || hasLeft
+}
+
 // Helper functions to convert operations to strings
 func boolOpToString(op exp.BooleanOperation) string {
 	switch op {
@@ -166,6 +266,32 @@ func boolOpToString(op exp.BooleanOperation) string {
 		return "iLike"
 	case exp.NotILikeOp:
 		return "notILike"
+	case exp.RegexpLikeOp:
+		return "regexpLike"
+	case exp.RegexpNotLikeOp:
+		return "regexpNotLike"
+	case exp.RegexpILikeOp:
+		return "regexpILike"
+	case exp.RegexpNotILikeOp:
+		return "regexpNotILike"
+	case JSONContainsOp:
+		return "jsonContains"
+	case JSONHasKeyOp:
+		return "jsonHasKey"
+	case ArrayContainsOp:
+		return "arrayContains"
+	case ArrayContainedByOp:
+		return "arrayContainedBy"
+	case ArrayOverlapOp:
+		return "arrayOverlap"
+	case ArrayHasOp:
+		return "arrayHas"
+	case ContainsOp:
+		return "contains"
+	case StartsWithOp:
+		return "startsWith"
+	case EndsWithOp:
+		return "endsWith"
 	default:
 		return "eq"
 	}
@@ -201,6 +327,32 @@ func stringToBoolOp(s string) exp.BooleanOperation {
 		return exp.ILikeOp
 	case "notILike":
 		return exp.NotILikeOp
+	case "regexpLike":
+		return exp.RegexpLikeOp
+	case "regexpNotLike":
+		return exp.RegexpNotLikeOp
+	case "regexpILike":
+		return exp.RegexpILikeOp
+	case "regexpNotILike":
+		return exp.RegexpNotILikeOp
+	case "jsonContains":
+		return JSONContainsOp
+	case "jsonHasKey":
+		return JSONHasKeyOp
+	case "arrayContains":
+		return ArrayContainsOp
+	case "arrayContainedBy":
+		return ArrayContainedByOp
+	case "arrayOverlap":
+		return ArrayOverlapOp
+	case "arrayHas":
+		return ArrayHasOp
+	case "contains":
+		return ContainsOp
+	case "startsWith":
+		return StartsWithOp
+	case "endsWith":
+		return EndsWithOp
 	default:
 		return exp.EqOp
 	}
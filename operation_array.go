@@ -0,0 +1,104 @@
+package supersaiyan
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const (
+	// ArrayContainsOp matches when the left array contains every value (@>).
+	ArrayContainsOp exp.BooleanOperation = iota + 110
+	// ArrayContainedByOp matches when every element of the left array is one of the values (<@).
+	ArrayContainedByOp
+	// ArrayOverlapOp matches when the left array shares at least one value (&&).
+	ArrayOverlapOp
+	// ArrayHasOp matches when the left array has the single value as an element (? = ANY(array)).
+	ArrayHasOp
+)
+
+// arrayOperators maps the array comparisons taking an array value to their SQL operator.
+var arrayOperators = map[exp.BooleanOperation]string{
+	ArrayContainsOp:    "@>",
+	ArrayContainedByOp: "<@",
+	ArrayOverlapOp:     "&&",
+}
+
+// arrayExpression renders the PostgreSQL array operators. Go slices are bound as a single
+// array parameter; other values, such as another array column, are used as is.
+func (bo BoolOp) arrayExpression(rc *renderContext, left comparableExpression) exp.Expression {
+	if !capabilitiesOf(rc.dialect).Arrays {
+		rc.fail(fmt.Errorf(
+			"%w: %s needs PostgreSQL arrays, got %q",
+			ErrUnsupportedByDialect,
+			boolOpToString(bo.Op),
+			rc.dialect,
+		))
+		return nil
+	}
+
+	if bo.Op == ArrayHasOp {
+		return goqu.L("(? = ANY(?))", handleAny(rc, bo.Value), left)
+	}
+
+	var value any
+	list := reflect.Indirect(reflect.ValueOf(bo.Value))
+	if list.Kind() == reflect.Slice && list.Type().Elem().Kind() != reflect.Uint8 {
+		value = pgArray{values: list}
+	} else {
+		value = handleAny(rc, bo.Value)
+	}
+
+	return goqu.L("(? "+arrayOperators[bo.Op]+" ?)", left, value)
+}
+
+// ArrayContains creates an array containment comparison (@>).
+//
+// Examples:
+//
+//	ArrayContains("tags", "p", []string{"sale", "new"})
+//	// ("p"."tags" @> ?) bound to '{"sale","new"}'
+func ArrayContains(fieldName, tableAlias string, values any) BoolOp {
+	return BoolOp{
+		Op:         ArrayContainsOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      values,
+	}
+}
+
+// ArrayContainedBy creates an array containment comparison in the other direction (<@).
+func ArrayContainedBy(fieldName, tableAlias string, values any) BoolOp {
+	return BoolOp{
+		Op:         ArrayContainedByOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      values,
+	}
+}
+
+// ArrayOverlap creates an array overlap comparison (&&).
+func ArrayOverlap(fieldName, tableAlias string, values any) BoolOp {
+	return BoolOp{
+		Op:         ArrayOverlapOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      values,
+	}
+}
+
+// ArrayHas creates an array element check.
+//
+// Examples:
+//
+//	ArrayHas("tags", "p", "sale") // (? = ANY("p"."tags"))
+func ArrayHas(fieldName, tableAlias string, value any) BoolOp {
+	return BoolOp{
+		Op:         ArrayHasOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      value,
+	}
+}
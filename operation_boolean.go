@@ -5,66 +5,110 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
 )
 
 // BoolOp represents a boolean comparison operation (=, !=, >, <, LIKE, IN, etc.).
+// The left operand is Left when set (a Field, Literal, Case, Coalesce or goqu expression),
+// otherwise the column FieldName qualified by TableAlias. A Quantifier compares against
+// ANY or ALL values of a subquery or list instead of a single value.
 type BoolOp struct {
 	Op         exp.BooleanOperation `json:"op"                   yaml:"op"`
-	FieldName  string               `json:"fieldName"            yaml:"fieldName"`
+	FieldName  string               `json:"fieldName,omitempty"  yaml:"fieldName,omitempty"`
 	TableAlias string               `json:"tableAlias,omitempty" yaml:"tableAlias,omitempty"`
+	Left       any                  `json:"left,omitempty"       yaml:"left,omitempty"`
+	Quantifier Quantifier           `json:"quantifier,omitempty" yaml:"quantifier,omitempty"`
 	Value      any                  `json:"value"                yaml:"value"`
 }
 
 // expression converts the BoolOp to a goqu boolean expression.
-func (bo BoolOp) expression() exp.Expression {
-	field := Field{
-		Name:       bo.FieldName,
-		TableAlias: bo.TableAlias,
+func (bo BoolOp) expression(rc *renderContext) exp.Expression {
+	left := leftOperand(rc, bo.Left, bo.FieldName, bo.TableAlias)
+	if bo.Quantifier != NoQuantifier {
+		return bo.quantifiedExpression(rc, left)
 	}
 
 	switch bo.Op {
 	case exp.EqOp:
-		return field.identifierExpression().Eq(handleAny(bo.Value))
+		return left.Eq(handleAny(rc, bo.Value))
 	case exp.NeqOp:
-		return field.identifierExpression().Neq(handleAny(bo.Value))
+		return left.Neq(handleAny(rc, bo.Value))
 	case exp.IsOp:
-		return field.identifierExpression().Is(handleAny(bo.Value))
+		return left.Is(handleAny(rc, bo.Value))
 	case exp.IsNotOp:
-		return field.identifierExpression().IsNot(handleAny(bo.Value))
+		return left.IsNot(handleAny(rc, bo.Value))
 	case exp.GtOp:
-		return field.identifierExpression().Gt(handleAny(bo.Value))
+		return left.Gt(handleAny(rc, bo.Value))
 	case exp.GteOp:
-		return field.identifierExpression().Gte(handleAny(bo.Value))
+		return left.Gte(handleAny(rc, bo.Value))
 	case exp.LtOp:
-		return field.identifierExpression().Lt(handleAny(bo.Value))
+		return left.Lt(handleAny(rc, bo.Value))
 	case exp.LteOp:
-		return field.identifierExpression().Lte(handleAny(bo.Value))
+		return left.Lte(handleAny(rc, bo.Value))
 	case exp.InOp:
-		return field.identifierExpression().In(handleAny(bo.Value))
+		return left.In(handleAny(rc, bo.Value))
 	case exp.NotInOp:
-		return field.identifierExpression().NotIn(handleAny(bo.Value))
-	case exp.LikeOp:
-		return field.identifierExpression().Like(handleAny(bo.Value))
-	case exp.NotLikeOp:
-		return field.identifierExpression().NotLike(handleAny(bo.Value))
-	case exp.ILikeOp:
-		return field.identifierExpression().ILike(handleAny(bo.Value))
-	case exp.NotILikeOp:
-		return field.identifierExpression().NotILike(handleAny(bo.Value))
-	case exp.RegexpLikeOp:
-		return field.identifierExpression().RegexpLike(handleAny(bo.Value))
-	case exp.RegexpNotLikeOp:
-		return field.identifierExpression().RegexpNotLike(handleAny(bo.Value))
-	case exp.RegexpILikeOp:
-		return field.identifierExpression().RegexpILike(handleAny(bo.Value))
-	case exp.RegexpNotILikeOp:
-		return field.identifierExpression().RegexpNotILike(handleAny(bo.Value))
+		return left.NotIn(handleAny(rc, bo.Value))
+	case exp.LikeOp, exp.NotLikeOp, exp.ILikeOp, exp.NotILikeOp,
+		ContainsOp, StartsWithOp, EndsWithOp:
+		return bo.likeExpression(rc, left)
+	case exp.RegexpLikeOp, exp.RegexpNotLikeOp, exp.RegexpILikeOp, exp.RegexpNotILikeOp:
+		return bo.regexpExpression(rc, left)
+	case JSONContainsOp, JSONHasKeyOp:
+		return bo.jsonExpression(rc, left)
+	case ArrayContainsOp, ArrayContainedByOp, ArrayOverlapOp, ArrayHasOp:
+		return bo.arrayExpression(rc, left)
 	default:
 		return nil
 	}
 }
 
+// regexpExpression renders the regular expression operators with the dialect's syntax:
+// ~ operators on PostgreSQL, REGEXP_LIKE on MySQL and the REGEXP operator on SQLite, which
+// has no case-insensitive form.
+func (bo BoolOp) regexpExpression(rc *renderContext, left comparableExpression) exp.Expression {
+	value := handleAny(rc, bo.Value)
+	negated := bo.Op == exp.RegexpNotLikeOp || bo.Op == exp.RegexpNotILikeOp
+	insensitive := bo.Op == exp.RegexpILikeOp || bo.Op == exp.RegexpNotILikeOp
+
+	switch capabilitiesOf(rc.dialect).Regexp {
+	case regexpOperators:
+		switch bo.Op {
+		case exp.RegexpNotLikeOp:
+			return left.RegexpNotLike(value)
+		case exp.RegexpILikeOp:
+			return left.RegexpILike(value)
+		case exp.RegexpNotILikeOp:
+			return left.RegexpNotILike(value)
+		default:
+			return left.RegexpLike(value)
+		}
+
+	case regexpLikeFunction:
+		matchType := "'c'"
+		if insensitive {
+			matchType = "'i'"
+		}
+		if negated {
+			return goqu.L("(NOT REGEXP_LIKE(?, ?, "+matchType+"))", left, value)
+		}
+		return goqu.L("REGEXP_LIKE(?, ?, "+matchType+")", left, value)
+
+	case regexpOperatorCaseSensitive:
+		if insensitive {
+			break
+		}
+		if negated {
+			return goqu.L("(? NOT REGEXP ?)", left, value)
+		}
+		return goqu.L("(? REGEXP ?)", left, value)
+	}
+
+	rc.fail(fmt.Errorf("%w: %s on %q", ErrUnsupportedByDialect, boolOpToString(bo.Op), rc.dialect))
+	return nil
+}
+
 // ParseBoolOperation converts a string to a goqu BooleanOperation.
 // Supported operators: =, !=, <>, >, >=, <, <=, IS, IS NOT, IN, NOT IN, LIKE, NOT LIKE, ILIKE, NOT ILIKE, ~, !~, ~*, !~*
 func ParseBoolOperation(s string) exp.BooleanOperation {
@@ -152,6 +196,8 @@ func (bo *BoolOp) UnmarshalJSON(data []byte) error {
 		Op         string          `json:"op"`
 		FieldName  string          `json:"fieldName"`
 		TableAlias string          `json:"tableAlias,omitempty"`
+		Left       json.RawMessage `json:"left,omitempty"`
+		Quantifier Quantifier      `json:"quantifier,omitempty"`
 		Value      json.RawMessage `json:"value"`
 	}{}
 
@@ -162,6 +208,15 @@ func (bo *BoolOp) UnmarshalJSON(data []byte) error {
 	bo.Op = stringToBoolOp(aux.Op)
 	bo.FieldName = aux.FieldName
 	bo.TableAlias = aux.TableAlias
+	bo.Quantifier = aux.Quantifier
+
+	if len(aux.Left) > 0 {
+		left, err := unmarshalExpression(aux.Left)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal left: %w", err)
+		}
+		bo.Left = left
+	}
 
 	// Try to unmarshal Value as an expression first
 	if len(aux.Value) > 0 {
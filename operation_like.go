@@ -0,0 +1,123 @@
+package supersaiyan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const (
+	// ContainsOp matches when the left operand contains the text literally (LIKE '%text%').
+	ContainsOp exp.BooleanOperation = iota + 120
+	// StartsWithOp matches when the left operand starts with the text (LIKE 'text%').
+	StartsWithOp
+	// EndsWithOp matches when the left operand ends with the text (LIKE '%text').
+	EndsWithOp
+)
+
+// likePatterns wrap escaped text into the pattern of each literal text match.
+var likePatterns = map[exp.BooleanOperation]func(string) string{
+	ContainsOp:   func(s string) string { return "%" + s + "%" },
+	StartsWithOp: func(s string) string { return s + "%" },
+	EndsWithOp:   func(s string) string { return "%" + s },
+}
+
+// likeEscaper escapes the LIKE wildcards, SQL Server's [ character classes and the backslash
+// escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+
+// escapeLikePattern escapes LIKE wildcards so the value is matched literally.
+func escapeLikePattern(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// likeExpression renders the LIKE family with backslash as the escape character on every
+// dialect, adding ESCAPE '\' where it is not the default. ILIKE is emulated as
+// LOWER(left) LIKE LOWER(value) on dialects without it, and LIKE is LIKE BINARY on MySQL to
+// stay case-sensitive.
+func (bo BoolOp) likeExpression(rc *renderContext, left comparableExpression) exp.Expression {
+	var value any
+	if pattern, ok := likePatterns[bo.Op]; ok {
+		text, isString := bo.Value.(string)
+		if !isString {
+			rc.fail(fmt.Errorf(
+				"%w: %s needs a string, got %T",
+				ErrInvalidCondition,
+				boolOpToString(bo.Op),
+				bo.Value,
+			))
+			return nil
+		}
+		value = goqu.V(pattern(escapeLikePattern(text)))
+	} else {
+		value = handleAny(rc, bo.Value)
+	}
+
+	caps := capabilitiesOf(rc.dialect)
+	operator := "LIKE"
+	var subject any = left
+	if bo.Op == exp.ILikeOp || bo.Op == exp.NotILikeOp {
+		if caps.ILike {
+			operator = "ILIKE"
+		} else {
+			subject = goqu.Func("LOWER", left)
+			value = goqu.Func("LOWER", value)
+		}
+	}
+	if caps.LikeBinary && (bo.Op == exp.LikeOp || bo.Op == exp.NotLikeOp) {
+		operator = "LIKE BINARY"
+	}
+	if bo.Op == exp.NotLikeOp || bo.Op == exp.NotILikeOp {
+		operator = "NOT " + operator
+	}
+
+	if caps.LikeBackslashEscape {
+		return goqu.L("(? "+operator+" ?)", subject, value)
+	}
+	return goqu.L("(? "+operator+" ? ESCAPE '\\')", subject, value)
+}
+
+// Contains creates a condition matching text anywhere in the field. Wildcards in text are
+// escaped, so it is matched literally.
+//
+// Examples:
+//
+//	Contains("name", "u", "50%") // ("u"."name" LIKE ?) bound to %50\%%
+func Contains(fieldName, tableAlias string, text string) BoolOp {
+	return BoolOp{
+		Op:         ContainsOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      text,
+	}
+}
+
+// StartsWith creates a condition matching fields starting with text, matched literally.
+//
+// Examples:
+//
+//	StartsWith("code", "p", "A_1") // ("p"."code" LIKE ?) bound to A\_1%
+func StartsWith(fieldName, tableAlias string, text string) BoolOp {
+	return BoolOp{
+		Op:         StartsWithOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      text,
+	}
+}
+
+// EndsWith creates a condition matching fields ending with text, matched literally.
+//
+// Examples:
+//
+//	EndsWith("email", "u", "@example.com") // ("u"."email" LIKE ?) bound to %@example.com
+func EndsWith(fieldName, tableAlias string, text string) BoolOp {
+	return BoolOp{
+		Op:         EndsWithOp,
+		FieldName:  fieldName,
+		TableAlias: tableAlias,
+		Value:      text,
+	}
+}
@@ -0,0 +1,194 @@
+package supersaiyan
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+var (
+	// ErrInvalidCondition is returned when a condition combines options that cannot be rendered.
+	ErrInvalidCondition = errors.New("invalid condition")
+
+	// ErrUnsupportedByDialect is returned when a condition cannot be rendered for the dialect.
+	ErrUnsupportedByDialect = errors.New("not supported by dialect")
+)
+
+// Quantifier turns a BoolOp comparison into a quantified comparison (= ANY, > ALL, ...)
+// against a subquery or a list of values.
+type Quantifier string
+
+const (
+	// NoQuantifier is a plain comparison (default).
+	NoQuantifier Quantifier = ""
+	// AnyQuantifier matches when the comparison holds for at least one value.
+	AnyQuantifier Quantifier = "ANY"
+	// AllQuantifier matches when the comparison holds for every value.
+	AllQuantifier Quantifier = "ALL"
+	// SomeQuantifier is the SQL standard synonym of AnyQuantifier.
+	SomeQuantifier Quantifier = "SOME"
+)
+
+// quantifiedOperators maps the comparisons that accept a quantifier to their SQL operator.
+var quantifiedOperators = map[exp.BooleanOperation]string{
+	exp.EqOp:  "=",
+	exp.NeqOp: "<>",
+	exp.GtOp:  ">",
+	exp.GteOp: ">=",
+	exp.LtOp:  "<",
+	exp.LteOp: "<=",
+}
+
+// Any compares against ANY value of a subquery or list. A list renders as a single array
+// parameter on PostgreSQL and as IN elsewhere (only for =).
+//
+// Examples:
+//
+//	Eq("id", "u", []int{1, 2, 3}).Any() // "u"."id" = ANY(?) bound to '{1,2,3}'
+//	Lt("price", "p", rivalPrices).Any() // "p"."price" < ANY (SELECT ...)
+func (bo BoolOp) Any() BoolOp {
+	bo.Quantifier = AnyQuantifier
+	return bo
+}
+
+// All compares against ALL values of a subquery or list. A list renders as a single array
+// parameter on PostgreSQL and as NOT IN elsewhere (only for != / <>).
+//
+// Examples:
+//
+//	Gt("price", "p", subquery).All() // "p"."price" > ALL (SELECT ...)
+func (bo BoolOp) All() BoolOp {
+	bo.Quantifier = AllQuantifier
+	return bo
+}
+
+// Some is the SQL standard synonym of Any.
+func (bo BoolOp) Some() BoolOp {
+	bo.Quantifier = SomeQuantifier
+	return bo
+}
+
+// quantifiedExpression renders a BoolOp that has a quantifier. Subqueries work on every
+// dialect except SQLite; lists bind one array parameter on PostgreSQL and fall back to
+// IN / NOT IN where that is equivalent.
+func (bo BoolOp) quantifiedExpression(rc *renderContext, left comparableExpression) exp.Expression {
+	sqlOp, ok := quantifiedOperators[bo.Op]
+	if !ok {
+		rc.fail(fmt.Errorf(
+			"%w: %s cannot be used with %s",
+			ErrInvalidCondition,
+			bo.Quantifier,
+			boolOpToString(bo.Op),
+		))
+		return nil
+	}
+
+	switch bo.Quantifier {
+	case AnyQuantifier, AllQuantifier, SomeQuantifier:
+	default:
+		rc.fail(fmt.Errorf("%w: unknown quantifier %q", ErrInvalidCondition, bo.Quantifier))
+		return nil
+	}
+
+	if isSubquery(bo.Value) {
+		if !capabilitiesOf(rc.dialect).QuantifiedSubqueries {
+			rc.fail(fmt.Errorf(
+				"%w: %s subqueries on %s",
+				ErrUnsupportedByDialect,
+				bo.Quantifier,
+				rc.dialect,
+			))
+			return nil
+		}
+		return goqu.L(fmt.Sprintf("? %s %s ?", sqlOp, bo.Quantifier), left, handleAny(rc, bo.Value))
+	}
+
+	list := reflect.Indirect(reflect.ValueOf(bo.Value))
+	isList := list.Kind() == reflect.Slice && list.Type().Elem().Kind() != reflect.Uint8
+
+	if capabilitiesOf(rc.dialect).Arrays {
+		var value any = handleAny(rc, bo.Value)
+		if isList {
+			value = pgArray{values: list}
+		}
+		return goqu.L(fmt.Sprintf("? %s %s(?)", sqlOp, bo.Quantifier), left, value)
+	}
+
+	switch {
+	case isList && bo.Op == exp.EqOp && bo.Quantifier != AllQuantifier:
+		return left.In(handleAny(rc, bo.Value))
+	case isList && bo.Op == exp.NeqOp && bo.Quantifier == AllQuantifier:
+		return left.NotIn(handleAny(rc, bo.Value))
+	}
+
+	rc.fail(fmt.Errorf(
+		"%w: %s %s over a list on %q",
+		ErrUnsupportedByDialect,
+		sqlOp,
+		bo.Quantifier,
+		rc.dialect,
+	))
+	return nil
+}
+
+// isSubquery reports whether v is a SQLBuilder used as a subquery.
+func isSubquery(v any) bool {
+	switch v.(type) {
+	case SQLBuilder, *SQLBuilder:
+		return true
+	default:
+		return false
+	}
+}
+
+// pgArray binds a slice as a single PostgreSQL array parameter in its text form,
+// so "= ANY(?)" keeps one placeholder regardless of the number of values.
+type pgArray struct {
+	values reflect.Value
+}
+
+// Value implements driver.Valuer.
+func (a pgArray) Value() (driver.Value, error) {
+	var sb strings.Builder
+	sb.WriteByte('{')
+
+	for i := 0; i < a.values.Len(); i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		elem := a.values.Index(i)
+		if (elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr) && elem.IsNil() {
+			sb.WriteString("NULL")
+			continue
+		}
+
+		switch v := reflect.Indirect(elem).Interface().(type) {
+		case string:
+			sb.WriteString(quotePGArrayElement(v))
+		case bool:
+			sb.WriteString(strconv.FormatBool(v))
+		case time.Time:
+			sb.WriteString(quotePGArrayElement(v.Format(time.RFC3339Nano)))
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			sb.WriteString(fmt.Sprint(v))
+		default:
+			return nil, fmt.Errorf("%w: cannot bind %T in an array", ErrInvalidCondition, v)
+		}
+	}
+
+	sb.WriteByte('}')
+	return sb.String(), nil
+}
+
+// quotePGArrayElement double-quotes an array element, escaping quotes and backslashes.
+func quotePGArrayElement(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -10,28 +10,26 @@ import (
 )
 
 // RangeOp represents a BETWEEN or NOT BETWEEN operation.
+// Like BoolOp, the left operand is Left when set, otherwise the column FieldName.
 type RangeOp struct {
 	Op         exp.RangeOperation `json:"op"                   yaml:"op"`
-	FieldName  string             `json:"fieldName"            yaml:"fieldName"`
+	FieldName  string             `json:"fieldName,omitempty"  yaml:"fieldName,omitempty"`
 	TableAlias string             `json:"tableAlias,omitempty" yaml:"tableAlias,omitempty"`
+	Left       any                `json:"left,omitempty"       yaml:"left,omitempty"`
 	Start      any                `json:"start"                yaml:"start"`
 	End        any                `json:"end"                  yaml:"end"`
 }
 
 // expression converts the RangeOp to a goqu range expression.
-func (ro RangeOp) expression() exp.Expression {
-	field := Field{
-		Name:       ro.FieldName,
-		TableAlias: ro.TableAlias,
-	}
-
-	rangeVal := goqu.Range(handleAny(ro.Start), handleAny(ro.End))
+func (ro RangeOp) expression(rc *renderContext) exp.Expression {
+	left := leftOperand(rc, ro.Left, ro.FieldName, ro.TableAlias)
+	rangeVal := goqu.Range(handleAny(rc, ro.Start), handleAny(rc, ro.End))
 
 	switch ro.Op {
 	case exp.NotBetweenOp:
-		return field.identifierExpression().NotBetween(rangeVal)
+		return left.NotBetween(rangeVal)
 	default:
-		return field.identifierExpression().Between(rangeVal)
+		return left.Between(rangeVal)
 	}
 }
 
@@ -64,6 +62,7 @@ func (ro *RangeOp) UnmarshalJSON(data []byte) error {
 		Op         string          `json:"op"`
 		FieldName  string          `json:"fieldName"`
 		TableAlias string          `json:"tableAlias,omitempty"`
+		Left       json.RawMessage `json:"left,omitempty"`
 		Start      json.RawMessage `json:"start"`
 		End        json.RawMessage `json:"end"`
 	}{}
@@ -76,6 +75,14 @@ func (ro *RangeOp) UnmarshalJSON(data []byte) error {
 	ro.FieldName = aux.FieldName
 	ro.TableAlias = aux.TableAlias
 
+	if len(aux.Left) > 0 {
+		left, err := unmarshalExpression(aux.Left)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal left: %w", err)
+		}
+		ro.Left = left
+	}
+
 	// Unmarshal Start
 	if len(aux.Start) > 0 {
 		start, err := unmarshalValue(aux.Start)
@@ -0,0 +1,307 @@
+package supersaiyan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// SearchMode selects how the query text of a Search is interpreted.
+type SearchMode string
+
+const (
+	// PlainSearch matches rows containing every word of the query (default).
+	PlainSearch SearchMode = "plain"
+	// PhraseSearch matches the query as an exact phrase.
+	PhraseSearch SearchMode = "phrase"
+	// BooleanSearch passes the query in the engine's own operator syntax:
+	// websearch_to_tsquery on PostgreSQL, BOOLEAN MODE on MySQL, FTS5 queries on SQLite
+	// and CONTAINS conditions on SQL Server.
+	BooleanSearch SearchMode = "boolean"
+)
+
+// Search represents a full-text search condition over one or more columns, rendered with
+// each dialect's full-text engine. The columns need a full-text index on MySQL and SQL Server
+// and must belong to an FTS5 table on SQLite, where each column is matched separately.
+// Language names the PostgreSQL text search configuration and is inlined so the expression
+// can use an index on to_tsvector('<language>', ...); several columns are joined with ||
+// rather than CONCAT_WS, which is not immutable and cannot be indexed.
+type Search struct {
+	Columns  []Field    `json:"search"             yaml:"search"`
+	Query    string     `json:"query"              yaml:"query"`
+	Mode     SearchMode `json:"mode,omitempty"     yaml:"mode,omitempty"`
+	Language string     `json:"language,omitempty" yaml:"language,omitempty"`
+}
+
+// SearchRank is the relevance of a Search match, higher being more relevant, for use as
+// a Field or Sort expression. It is not available on SQL Server, which ranks through
+// CONTAINSTABLE.
+type SearchRank struct {
+	Search Search `json:"rank" yaml:"rank"`
+}
+
+// searchLanguagePattern matches PostgreSQL text search configuration names.
+var searchLanguagePattern = regexp.MustCompile(`^[a-z_]+$`)
+
+// mysqlSearchOperators are stripped from words of plain MySQL boolean mode queries.
+var mysqlSearchOperators = strings.NewReplacer(
+	"+", "", "-", "", "<", "", ">", "", "(", "", ")", "", "~", "", "*", "", `"`, "", "@", "",
+)
+
+// expression converts the Search to a goqu expression for the query's dialect.
+func (s Search) expression(rc *renderContext) exp.Expression {
+	if err := s.validate(); err != nil {
+		rc.fail(err)
+		return nil
+	}
+
+	columns := s.columnExpressions(rc)
+	switch rc.dialect {
+	case "mysql":
+		return s.mysqlMatch(columns)
+
+	case "sqlite3":
+		query := s.quotedQuery(" ")
+		if len(columns) == 1 {
+			return goqu.L("(? MATCH ?)", columns[0], query)
+		}
+		matches := make([]exp.Expression, len(columns))
+		for i, column := range columns {
+			matches[i] = goqu.L("(? MATCH ?)", column, query)
+		}
+		return goqu.Or(matches...)
+
+	case "sqlserver":
+		return goqu.L(
+			"CONTAINS((?), ?)",
+			exp.NewColumnListExpression(columns...),
+			s.quotedQuery(" AND "),
+		)
+
+	default:
+		return goqu.L("(? @@ ?)", s.tsVector(columns), s.tsQuery())
+	}
+}
+
+// validate checks that the Search can be rendered.
+func (s Search) validate() error {
+	switch {
+	case len(s.Columns) == 0:
+		return fmt.Errorf("%w: search needs at least one column", ErrInvalidCondition)
+	case strings.TrimSpace(s.Query) == "":
+		return fmt.Errorf("%w: search query is empty", ErrInvalidCondition)
+	case s.Language != "" && !searchLanguagePattern.MatchString(s.Language):
+		return fmt.Errorf("%w: invalid search language %q", ErrInvalidCondition, s.Language)
+	}
+
+	switch s.Mode {
+	case "", PlainSearch, PhraseSearch, BooleanSearch:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown search mode %q", ErrInvalidCondition, s.Mode)
+	}
+}
+
+// columnExpressions resolves the searched columns, dropping field aliases.
+func (s Search) columnExpressions(rc *renderContext) []any {
+	columns := make([]any, len(s.Columns))
+	for i, column := range s.Columns {
+		columns[i] = leftOperand(rc, column, "", "")
+	}
+	return columns
+}
+
+// tsVector renders the PostgreSQL document of the searched columns, joined by spaces with
+// NULL columns read as empty.
+func (s Search) tsVector(columns []any) exp.LiteralExpression {
+	var document any = columns[0]
+	if len(columns) > 1 {
+		rest := strings.Repeat(" || ' ' || COALESCE(?, '')", len(columns)-1)
+		document = goqu.L("COALESCE(?, '')"+rest, columns...)
+	}
+	if s.Language == "" {
+		return goqu.L("TO_TSVECTOR(?)", document)
+	}
+	return goqu.L("TO_TSVECTOR('"+s.Language+"', ?)", document)
+}
+
+// tsQuery renders the PostgreSQL query of the search text.
+func (s Search) tsQuery() exp.LiteralExpression {
+	fn := "PLAINTO_TSQUERY"
+	switch s.Mode {
+	case PhraseSearch:
+		fn = "PHRASETO_TSQUERY"
+	case BooleanSearch:
+		fn = "WEBSEARCH_TO_TSQUERY"
+	}
+	if s.Language == "" {
+		return goqu.L(fn+"(?)", s.Query)
+	}
+	return goqu.L(fn+"('"+s.Language+"', ?)", s.Query)
+}
+
+// mysqlMatch renders MATCH ... AGAINST in boolean mode, which is also the relevance score.
+// Plain searches require every word with +"word".
+func (s Search) mysqlMatch(columns []any) exp.LiteralExpression {
+	query := s.Query
+	switch s.Mode {
+	case PhraseSearch:
+		query = `"` + strings.ReplaceAll(s.Query, `"`, "") + `"`
+	case "", PlainSearch:
+		words := make([]string, 0)
+		for _, word := range strings.Fields(s.Query) {
+			if word = mysqlSearchOperators.Replace(word); word != "" {
+				words = append(words, `+"`+word+`"`)
+			}
+		}
+		query = strings.Join(words, " ")
+	}
+
+	return goqu.L(
+		"MATCH (?) AGAINST (? IN BOOLEAN MODE)",
+		exp.NewColumnListExpression(columns...),
+		query,
+	)
+}
+
+// quotedQuery builds an FTS5 or CONTAINS query: plain searches quote each word and join
+// them with sep, phrase searches quote the whole text and boolean searches pass it as is.
+func (s Search) quotedQuery(sep string) string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+
+	switch s.Mode {
+	case PhraseSearch:
+		return quote(strings.Join(strings.Fields(s.Query), " "))
+	case BooleanSearch:
+		return s.Query
+	default:
+		words := strings.Fields(s.Query)
+		for i, word := range words {
+			words[i] = quote(word)
+		}
+		return strings.Join(words, sep)
+	}
+}
+
+// Rank returns the relevance of the search for selecting or sorting.
+//
+// Examples:
+//
+//	search := FullText("laptop stand", F("name", WithTable("p")), F("summary", WithTable("p")))
+//	qb.Where(search).
+//		WithFields(Exp("relevance", search.Rank())).
+//		OrderBy(DescExp(search.Rank()))
+func (s Search) Rank() SearchRank {
+	return SearchRank{Search: s}
+}
+
+// expression converts the SearchRank to a goqu literal expression for the query's dialect.
+func (r SearchRank) expression(rc *renderContext) exp.LiteralExpression {
+	s := r.Search
+	if err := s.validate(); err != nil {
+		rc.fail(err)
+		return goqu.L("NULL")
+	}
+
+	columns := s.columnExpressions(rc)
+	switch rc.dialect {
+	case "mysql":
+		return s.mysqlMatch(columns)
+	case "sqlite3":
+		// FTS5's rank is lower for better matches
+		return goqu.L("(-?)", goqu.C("rank").Table(s.Columns[0].TableAlias))
+	case "sqlserver":
+		rc.fail(fmt.Errorf("%w: search rank on %q", ErrUnsupportedByDialect, rc.dialect))
+		return goqu.L("NULL")
+	default:
+		return goqu.L("TS_RANK(?, ?)", s.tsVector(columns), s.tsQuery())
+	}
+}
+
+// SearchFields builds the condition of a search box matching input across fields: every word,
+// or "quoted phrase", must appear case-insensitively in at least one of the fields. LIKE
+// wildcards in the input are escaped so they match literally. Input without words yields an
+// empty group, which adds no condition; a search without fields fails the query instead of
+// matching every row.
+//
+// Examples:
+//
+//	SearchFields(`jo "new york"`, F("name", WithTable("u")), F("city", WithTable("a")))
+//	// ("u"."name" ILIKE ? OR "a"."city" ILIKE ?) AND ("u"."name" ILIKE ? OR "a"."city" ILIKE ?)
+//	// bound to %jo%, %jo%, %new york%, %new york%
+func SearchFields(input string, fields ...Field) WhereGroup {
+	if len(fields) == 0 {
+		err := fmt.Errorf("%w: search needs at least one field", ErrInvalidCondition)
+		return And(invalidCondition{err: err})
+	}
+
+	terms := searchTerms(input)
+	group := make([]any, len(terms))
+	for i, term := range terms {
+		pattern := "%" + escapeLikePattern(term) + "%"
+		alternatives := make([]any, len(fields))
+		for j, field := range fields {
+			alternatives[j] = fieldBoolOp(exp.ILikeOp, field, pattern)
+		}
+		group[i] = Or(alternatives...)
+	}
+	return And(group...)
+}
+
+// invalidCondition fails the query it is rendered in with err.
+type invalidCondition struct {
+	err error
+}
+
+// toExpression records the error and renders nothing.
+func (ic invalidCondition) toExpression(rc *renderContext) exp.Expression {
+	rc.fail(ic.err)
+	return nil
+}
+
+// searchTerms splits search box input into words, keeping "quoted phrases" together.
+// An unterminated quote runs to the end of the input.
+func searchTerms(input string) []string {
+	var terms []string
+	for i, part := range strings.Split(input, `"`) {
+		if i%2 == 0 {
+			terms = append(terms, strings.Fields(part)...)
+			continue
+		}
+		if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+			terms = append(terms, phrase)
+		}
+	}
+	return terms
+}
+
+// FullText creates a plain full-text search matching every word of query in the columns.
+//
+// Examples:
+//
+//	FullText("wireless mouse", F("name", WithTable("p")))
+//	// postgres: (TO_TSVECTOR("p"."name") @@ PLAINTO_TSQUERY(?))
+//	// mysql:    MATCH ("p"."name") AGAINST (? IN BOOLEAN MODE) bound to +"wireless" +"mouse"
+func FullText(query string, columns ...Field) Search {
+	return Search{
+		Columns: columns,
+		Query:   query,
+	}
+}
+
+// WithMode returns a copy of the search using mode.
+func (s Search) WithMode(mode SearchMode) Search {
+	s.Mode = mode
+	return s
+}
+
+// WithLanguage returns a copy of the search using a PostgreSQL text search configuration.
+func (s Search) WithLanguage(language string) Search {
+	s.Language = language
+	return s
+}
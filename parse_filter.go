@@ -0,0 +1,515 @@
+package supersaiyan
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// SyntaxError describes a problem found while parsing a filter expression.
+// Column is the 1-based character offset where the problem was detected.
+type SyntaxError struct {
+	Column int
+	Msg    string
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Column, e.Msg)
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFilter parses a filter expression into a Condition tree of BoolOp, RangeOp and WhereGroup.
+// Field names are resolved through fields, which also acts as the allow-list.
+//
+// The language supports the comparison operators listed in BoolOperatorStrings, IN lists,
+// BETWEEN ranges, IS [NOT] NULL, AND/OR/NOT and parentheses. Values are single-quoted strings
+// (a doubled quote escapes a quote), numbers, true, false and null. Keywords are case-insensitive.
+//
+// Examples:
+//
+//	ParseFilter("status = 'active' and (age >= 18 or role in ('admin', 'mod'))", fields)
+//	ParseFilter("u.created_at between '2024-01-01' and '2024-12-31'", AllowAnyField())
+func ParseFilter(s string, fields FieldResolver) (Condition, error) {
+	tokens, err := tokenizeFilter(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filterParser{tokenCursor: tokenCursor{tokens: tokens}, fields: fields}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+
+	return cond, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenIdent
+	filterTokenString
+	filterTokenNumber
+	filterTokenOperator
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenComma
+)
+
+// filterToken is a lexical token of the filter language.
+type filterToken struct {
+	kind   filterTokenKind
+	text   string
+	column int
+}
+
+// String describes the token for error messages.
+func (t filterToken) String() string {
+	switch t.kind {
+	case filterTokenEOF:
+		return "end of input"
+	case filterTokenString:
+		return fmt.Sprintf("string '%s'", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// keyword reports whether the token is the given case-insensitive keyword.
+func (t filterToken) keyword(kw string) bool {
+	return t.kind == filterTokenIdent && strings.EqualFold(t.text, kw)
+}
+
+// filterOperators lists the symbolic comparison operators, derived from BoolOperatorStrings.
+// Longer operators come first so that "!~*" is matched before "!~".
+var filterOperators = func() []string {
+	var ops []string
+	for _, op := range BoolOperatorStrings {
+		if op == strings.TrimSpace(op) && !unicode.IsLetter(rune(op[0])) {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}()
+
+// tokenizeFilter splits a filter expression into tokens.
+func tokenizeFilter(s string) ([]filterToken, error) {
+	runes := []rune(s)
+	var tokens []filterToken
+
+	emit := func(kind filterTokenKind, text string, column int) {
+		tokens = append(tokens, filterToken{kind: kind, text: text, column: column})
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			emit(filterTokenLParen, "(", column)
+			i++
+		case r == ')':
+			emit(filterTokenRParen, ")", column)
+			i++
+		case r == ',':
+			emit(filterTokenComma, ",", column)
+			i++
+		case r == '\'':
+			text, end, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			emit(filterTokenString, text, column)
+			i = end
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			emit(filterTokenNumber, string(runes[start:i]), column)
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && isFilterIdentRune(runes[i]) {
+				i++
+			}
+			emit(filterTokenIdent, string(runes[start:i]), column)
+		default:
+			matched := ""
+			for _, op := range filterOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					matched = op
+					break
+				}
+			}
+			if matched == "" {
+				msg := fmt.Sprintf("unexpected character %q", r)
+				return nil, &SyntaxError{Column: column, Msg: msg}
+			}
+			emit(filterTokenOperator, matched, column)
+			i += len([]rune(matched))
+		}
+	}
+
+	return append(tokens, filterToken{kind: filterTokenEOF, column: len(runes) + 1}), nil
+}
+
+// lexQuoted reads a single-quoted string starting at runes[start]; a doubled quote escapes a quote.
+// It returns the unescaped text and the index just past the closing quote.
+func lexQuoted(runes []rune, start int) (string, int, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != '\'' {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == '\'' {
+			sb.WriteRune('\'')
+			i++
+			continue
+		}
+		return sb.String(), i + 1, nil
+	}
+	return "", 0, &SyntaxError{Column: start + 1, Msg: "unterminated string"}
+}
+
+// isFilterIdentRune reports whether r may appear in a field name.
+func isFilterIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// tokenCursor walks a token stream terminated by an EOF token.
+type tokenCursor struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (c *tokenCursor) peek() filterToken {
+	return c.tokens[c.pos]
+}
+
+func (c *tokenCursor) next() filterToken {
+	tok := c.tokens[c.pos]
+	if tok.kind != filterTokenEOF {
+		c.pos++
+	}
+	return tok
+}
+
+func (c *tokenCursor) errorf(tok filterToken, format string, args ...any) error {
+	return &SyntaxError{Column: tok.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+// expectKeyword consumes the given keyword or returns a syntax error.
+func (c *tokenCursor) expectKeyword(kw string) error {
+	if tok := c.next(); !tok.keyword(kw) {
+		return c.errorf(tok, "expected %s, got %s", strings.ToUpper(kw), tok)
+	}
+	return nil
+}
+
+// expect consumes a token of the given kind or returns a syntax error.
+func (c *tokenCursor) expect(kind filterTokenKind, what string) (filterToken, error) {
+	tok := c.next()
+	if tok.kind != kind {
+		return tok, c.errorf(tok, "expected %s, got %s", what, tok)
+	}
+	return tok, nil
+}
+
+// resolveField resolves a field name token through fields, reporting unknown names at the token.
+func resolveField(fields FieldResolver, tok filterToken) (Field, error) {
+	field, err := lookupField(fields, tok.text)
+	if err != nil {
+		return Field{}, &SyntaxError{Column: tok.column, Msg: err.Error(), Err: err}
+	}
+	return field, nil
+}
+
+// filterParser is a recursive descent parser over filter tokens.
+type filterParser struct {
+	tokenCursor
+	fields FieldResolver
+}
+
+// parseOr parses a sequence of AND groups joined by OR.
+func (p *filterParser) parseOr() (Condition, error) {
+	return p.parseList(exp.OrType, "or", p.parseAnd)
+}
+
+// parseAnd parses a sequence of terms joined by AND.
+func (p *filterParser) parseAnd() (Condition, error) {
+	return p.parseList(exp.AndType, "and", p.parseTerm)
+}
+
+// parseList parses operands separated by a logical keyword into a WhereGroup.
+// A single operand is returned as-is.
+func (p *filterParser) parseList(
+	op exp.ExpressionListType,
+	kw string,
+	operand func() (Condition, error),
+) (Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []any{first}
+	for p.peek().keyword(kw) {
+		p.next()
+		cond, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	if len(conditions) == 1 {
+		return first, nil
+	}
+	return WhereGroup{Op: op, Conditions: conditions}, nil
+}
+
+// parseTerm parses a negated term, a parenthesized expression or a single comparison.
+func (p *filterParser) parseTerm() (Condition, error) {
+	tok := p.peek()
+
+	if tok.keyword("not") {
+		p.next()
+		cond, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return Not(cond), nil
+	}
+
+	if tok.kind == filterTokenLParen {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(filterTokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return cond, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses "field <operator> value" and its keyword forms.
+func (p *filterParser) parseComparison() (Condition, error) {
+	tok := p.next()
+	if tok.kind != filterTokenIdent || isFilterKeyword(tok.text) {
+		return nil, p.errorf(tok, "expected field name, got %s", tok)
+	}
+
+	field, err := resolveField(p.fields, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	opTok := p.next()
+	switch {
+	case opTok.kind == filterTokenOperator:
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		op := ParseBoolOperation(opTok.text)
+		// Comparing with NULL only makes sense as IS / IS NOT
+		if value == nil {
+			switch op {
+			case exp.EqOp:
+				op = exp.IsOp
+			case exp.NeqOp:
+				op = exp.IsNotOp
+			default:
+				return nil, p.errorf(opTok, "operator %s cannot be used with null", opTok.text)
+			}
+		}
+		return fieldBoolOp(op, field, value), nil
+
+	case opTok.keyword("is"):
+		op := exp.IsOp
+		if p.peek().keyword("not") {
+			p.next()
+			op = exp.IsNotOp
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if _, isBool := value.(bool); value != nil && !isBool {
+			return nil, p.errorf(opTok, "IS requires null, true or false")
+		}
+		return fieldBoolOp(op, field, value), nil
+
+	case opTok.keyword("not"):
+		next := p.next()
+		switch {
+		case next.keyword("in"):
+			values, err := p.parseValueList()
+			if err != nil {
+				return nil, err
+			}
+			return fieldBoolOp(exp.NotInOp, field, values), nil
+		case next.keyword("like"), next.keyword("ilike"):
+			return p.parsePattern(field, ParseBoolOperation("not "+next.text))
+		case next.keyword("between"):
+			return p.parseRange(field, exp.NotBetweenOp)
+		default:
+			return nil, p.errorf(
+				next,
+				"expected IN, LIKE, ILIKE or BETWEEN after NOT, got %s",
+				next,
+			)
+		}
+
+	case opTok.keyword("in"):
+		values, err := p.parseValueList()
+		if err != nil {
+			return nil, err
+		}
+		return fieldBoolOp(exp.InOp, field, values), nil
+
+	case opTok.keyword("like"), opTok.keyword("ilike"):
+		return p.parsePattern(field, ParseBoolOperation(opTok.text))
+
+	case opTok.keyword("between"):
+		return p.parseRange(field, exp.BetweenOp)
+
+	default:
+		return nil, p.errorf(opTok, "expected operator, got %s", opTok)
+	}
+}
+
+// parsePattern parses the string pattern of a LIKE or ILIKE comparison.
+func (p *filterParser) parsePattern(field Field, op exp.BooleanOperation) (Condition, error) {
+	tok := p.next()
+	if tok.kind != filterTokenString {
+		return nil, p.errorf(tok, "expected string pattern, got %s", tok)
+	}
+	return fieldBoolOp(op, field, tok.text), nil
+}
+
+// parseRange parses the "start AND end" part of a BETWEEN comparison.
+func (p *filterParser) parseRange(field Field, op exp.RangeOperation) (Condition, error) {
+	start, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("and"); err != nil {
+		return nil, err
+	}
+	end, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldRangeOp(op, field, start, end), nil
+}
+
+// parseValueList parses a parenthesized, comma-separated list of values.
+func (p *filterParser) parseValueList() ([]any, error) {
+	if _, err := p.expect(filterTokenLParen, "( to start a list"); err != nil {
+		return nil, err
+	}
+
+	var values []any
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		tok := p.next()
+		if tok.kind == filterTokenRParen {
+			return values, nil
+		}
+		if tok.kind != filterTokenComma {
+			return nil, p.errorf(tok, "expected , or ) in list, got %s", tok)
+		}
+	}
+}
+
+// parseValue parses a literal value: string, number, true, false or null.
+func (p *filterParser) parseValue() (any, error) {
+	tok := p.next()
+
+	switch {
+	case tok.kind == filterTokenString:
+		return tok.text, nil
+	case tok.kind == filterTokenNumber:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %s", tok)
+		}
+		return f, nil
+	case tok.keyword("null"):
+		return nil, nil
+	case tok.keyword("true"):
+		return true, nil
+	case tok.keyword("false"):
+		return false, nil
+	default:
+		return nil, p.errorf(tok, "expected value, got %s", tok)
+	}
+}
+
+// filterKeywords are reserved words that cannot be used as field names.
+var filterKeywords = []string{
+	"and", "or", "not", "in", "like", "ilike", "is", "null", "between", "true", "false",
+}
+
+func isFilterKeyword(s string) bool {
+	return slices.Contains(filterKeywords, strings.ToLower(s))
+}
+
+// fieldBoolOp creates a BoolOp comparing the given Field.
+// Expression fields become the left operand.
+func fieldBoolOp(op exp.BooleanOperation, field Field, value any) BoolOp {
+	return BoolOp{
+		Op:         op,
+		FieldName:  field.Name,
+		TableAlias: field.TableAlias,
+		Left:       field.Exp,
+		Value:      value,
+	}
+}
+
+// fieldRangeOp creates a RangeOp over the given Field.
+// Expression fields become the left operand.
+func fieldRangeOp(op exp.RangeOperation, field Field, start, end any) RangeOp {
+	return RangeOp{
+		Op:         op,
+		FieldName:  field.Name,
+		TableAlias: field.TableAlias,
+		Left:       field.Exp,
+		Start:      start,
+		End:        end,
+	}
+}
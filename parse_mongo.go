@@ -0,0 +1,422 @@
+package supersaiyan
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrInvalidFilter is returned when a MongoDB-style filter document is malformed or uses
+// an unsupported operator.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// mongoComparisons maps MongoDB comparison operators to goqu boolean operations.
+var mongoComparisons = map[string]exp.BooleanOperation{
+	"$eq":  exp.EqOp,
+	"$ne":  exp.NeqOp,
+	"$gt":  exp.GtOp,
+	"$gte": exp.GteOp,
+	"$lt":  exp.LtOp,
+	"$lte": exp.LteOp,
+	"$in":  exp.InOp,
+	"$nin": exp.NotInOp,
+}
+
+// negatedBoolOps maps each boolean operation to its logical opposite.
+var negatedBoolOps = map[exp.BooleanOperation]exp.BooleanOperation{
+	exp.EqOp:             exp.NeqOp,
+	exp.NeqOp:            exp.EqOp,
+	exp.IsOp:             exp.IsNotOp,
+	exp.IsNotOp:          exp.IsOp,
+	exp.GtOp:             exp.LteOp,
+	exp.GteOp:            exp.LtOp,
+	exp.LtOp:             exp.GteOp,
+	exp.LteOp:            exp.GtOp,
+	exp.InOp:             exp.NotInOp,
+	exp.NotInOp:          exp.InOp,
+	exp.LikeOp:           exp.NotLikeOp,
+	exp.NotLikeOp:        exp.LikeOp,
+	exp.ILikeOp:          exp.NotILikeOp,
+	exp.NotILikeOp:       exp.ILikeOp,
+	exp.RegexpLikeOp:     exp.RegexpNotLikeOp,
+	exp.RegexpNotLikeOp:  exp.RegexpLikeOp,
+	exp.RegexpILikeOp:    exp.RegexpNotILikeOp,
+	exp.RegexpNotILikeOp: exp.RegexpILikeOp,
+}
+
+// ParseMongoFilter decodes a MongoDB-style JSON filter into a Condition tree.
+//
+// Top-level keys are field names or the logical operators $and, $or and $nor; multiple keys
+// are combined with AND in document order. A field maps to a plain value (equality, or IS NULL
+// for null) or to an operator document using $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin,
+// $regex (with $options "i" for case-insensitive), $exists and $not. A $gte/$lte pair on the
+// same field becomes a BETWEEN range. As in MongoDB, negations ($ne, $nin, $not and $nor)
+// also match rows where the field is NULL. Duplicate keys in a document are rejected.
+// Field names are resolved through fields, which also acts as the allow-list.
+//
+// Examples:
+//
+//	ParseMongoFilter([]byte(`{"u.status": "active", "u.age": {"$gte": 18}}`), AllowAnyField())
+//	ParseMongoFilter([]byte(`{"$or": [{"role": {"$in": ["admin"]}}, {"verified": true}]}`), fields)
+func ParseMongoFilter(data []byte, fields FieldResolver) (Condition, error) {
+	return parseMongoDocument(data, fields)
+}
+
+// isMongoFilter reports whether a decoded JSON object looks like a MongoDB-style filter:
+// it has no "op" key and uses a "$" operator at the top level or in a field's operator document.
+func isMongoFilter(typeDetector map[string]json.RawMessage) bool {
+	if _, hasOp := typeDetector["op"]; hasOp {
+		return false
+	}
+
+	for key, raw := range typeDetector {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+
+		var operators map[string]json.RawMessage
+		if json.Unmarshal(raw, &operators) != nil {
+			continue
+		}
+		for op := range operators {
+			if strings.HasPrefix(op, "$") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// mongoEntry is a key/value pair of a JSON object.
+type mongoEntry struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeMongoObject decodes a JSON object into its entries, preserving document order.
+// Duplicate keys and data after the object are rejected rather than ignored.
+func decodeMongoObject(data []byte) ([]mongoEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("%w: expected an object", ErrInvalidFilter)
+	}
+
+	var entries []mongoEntry
+	seen := map[string]bool{}
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := keyTok.(string)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate key %q", ErrInvalidFilter, key)
+		}
+		seen[key] = true
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		entries = append(entries, mongoEntry{key: key, value: value})
+	}
+
+	// The closing brace must end the input, so trailing documents are not silently dropped
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after the object", ErrInvalidFilter)
+	}
+
+	return entries, nil
+}
+
+// parseMongoDocument converts a filter document into a condition, ANDing its entries.
+func parseMongoDocument(data []byte, fields FieldResolver) (Condition, error) {
+	entries, err := decodeMongoObject(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: empty filter document", ErrInvalidFilter)
+	}
+
+	conditions := make([]any, 0, len(entries))
+	for _, entry := range entries {
+		var cond Condition
+		switch entry.key {
+		case "$and", "$or", "$nor":
+			cond, err = parseMongoLogical(entry.key, entry.value, fields)
+		default:
+			if strings.HasPrefix(entry.key, "$") {
+				return nil, fmt.Errorf(
+					"%w: unknown top-level operator %q",
+					ErrInvalidFilter,
+					entry.key,
+				)
+			}
+			cond, err = parseMongoField(entry.key, entry.value, fields)
+		}
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	return combineConditions(exp.AndType, conditions), nil
+}
+
+// parseMongoLogical converts $and, $or and $nor arrays into WhereGroups.
+func parseMongoLogical(op string, data json.RawMessage, fields FieldResolver) (Condition, error) {
+	var docs []json.RawMessage
+	if err := json.Unmarshal(data, &docs); err != nil || len(docs) == 0 {
+		return nil, fmt.Errorf("%w: %s expects a non-empty array", ErrInvalidFilter, op)
+	}
+
+	conditions := make([]any, len(docs))
+	for i, doc := range docs {
+		cond, err := parseMongoDocument(doc, fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", op, i, err)
+		}
+		conditions[i] = cond
+	}
+
+	switch op {
+	case "$or":
+		return WhereGroup{Op: exp.OrType, Conditions: conditions}, nil
+	case "$nor":
+		return negateCondition(WhereGroup{Op: exp.OrType, Conditions: conditions}), nil
+	default:
+		return WhereGroup{Op: exp.AndType, Conditions: conditions}, nil
+	}
+}
+
+// parseMongoField converts the filter for a single field.
+func parseMongoField(name string, data json.RawMessage, fields FieldResolver) (Condition, error) {
+	field, err := lookupField(fields, name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := decodeMongoObject(data)
+	if err != nil && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err != nil || len(entries) == 0 || !strings.HasPrefix(entries[0].key, "$") {
+		// Not an operator document: plain equality
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		switch value.(type) {
+		case nil:
+			return fieldBoolOp(exp.IsOp, field, nil), nil
+		case []any, map[string]any:
+			return nil, fmt.Errorf(
+				"%w: %s: equality with arrays or documents is not supported",
+				ErrInvalidFilter,
+				name,
+			)
+		default:
+			return fieldBoolOp(exp.EqOp, field, value), nil
+		}
+	}
+
+	cond, err := parseMongoOperators(field, entries)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return cond, nil
+}
+
+// parseMongoOperators converts a field's operator document into conditions.
+func parseMongoOperators(field Field, entries []mongoEntry) (Condition, error) {
+	operators := make(map[string]any, len(entries))
+	var order []string
+	var notDoc []mongoEntry
+
+	for _, entry := range entries {
+		if entry.key == "$not" {
+			var err error
+			if notDoc, err = decodeMongoObject(entry.value); err != nil || len(notDoc) == 0 {
+				return nil, fmt.Errorf("%w: $not expects an operator document", ErrInvalidFilter)
+			}
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(entry.value, &value); err != nil {
+			return nil, err
+		}
+		operators[entry.key] = value
+		order = append(order, entry.key)
+	}
+
+	var conditions []any
+
+	// A closed range on one field reads better as BETWEEN
+	gte, hasGte := operators["$gte"]
+	lte, hasLte := operators["$lte"]
+	if hasGte && hasLte {
+		conditions = append(conditions, fieldRangeOp(exp.BetweenOp, field, gte, lte))
+	}
+
+	for _, op := range order {
+		value := operators[op]
+
+		switch op {
+		case "$gte", "$lte":
+			if hasGte && hasLte {
+				continue
+			}
+			conditions = append(conditions, fieldBoolOp(mongoComparisons[op], field, value))
+
+		case "$eq", "$ne", "$gt", "$lt":
+			boolOp := mongoComparisons[op]
+			if value == nil {
+				switch boolOp {
+				case exp.EqOp:
+					boolOp = exp.IsOp
+				case exp.NeqOp:
+					boolOp = exp.IsNotOp
+				default:
+					return nil, fmt.Errorf("%w: %s cannot be used with null", ErrInvalidFilter, op)
+				}
+			}
+			if boolOp == exp.NeqOp {
+				conditions = append(conditions, orNull(fieldBoolOp(boolOp, field, value), field))
+				continue
+			}
+			conditions = append(conditions, fieldBoolOp(boolOp, field, value))
+
+		case "$in", "$nin":
+			list, ok := value.([]any)
+			if !ok || len(list) == 0 {
+				return nil, fmt.Errorf("%w: %s expects a non-empty array", ErrInvalidFilter, op)
+			}
+			cond := fieldBoolOp(mongoComparisons[op], field, list)
+			if op == "$nin" {
+				conditions = append(conditions, orNull(cond, field))
+				continue
+			}
+			conditions = append(conditions, cond)
+
+		case "$exists":
+			exists, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: $exists expects a boolean", ErrInvalidFilter)
+			}
+			if exists {
+				conditions = append(conditions, fieldBoolOp(exp.IsNotOp, field, nil))
+			} else {
+				conditions = append(conditions, fieldBoolOp(exp.IsOp, field, nil))
+			}
+
+		case "$regex":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: $regex expects a string", ErrInvalidFilter)
+			}
+			regexOp := exp.RegexpLikeOp
+			if options, _ := operators["$options"].(string); strings.Contains(options, "i") {
+				regexOp = exp.RegexpILikeOp
+			}
+			conditions = append(conditions, fieldBoolOp(regexOp, field, pattern))
+
+		case "$options":
+			if _, hasRegex := operators["$regex"]; !hasRegex {
+				return nil, fmt.Errorf("%w: $options requires $regex", ErrInvalidFilter)
+			}
+
+		default:
+			return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, op)
+		}
+	}
+
+	if notDoc != nil {
+		inner, err := parseMongoOperators(field, notDoc)
+		if err != nil {
+			return nil, fmt.Errorf("$not: %w", err)
+		}
+		conditions = append(conditions, negateCondition(inner))
+	}
+
+	return combineConditions(exp.AndType, conditions), nil
+}
+
+// combineConditions wraps multiple conditions in a WhereGroup and returns a single one as-is.
+func combineConditions(op exp.ExpressionListType, conditions []any) Condition {
+	if len(conditions) == 1 {
+		if cond, ok := conditions[0].(Condition); ok {
+			return cond
+		}
+	}
+	return WhereGroup{Op: op, Conditions: conditions}
+}
+
+// orNull extends a negative condition on field to also match NULL, since MongoDB's negations
+// match documents where the field is missing or null while SQL comparisons with NULL do not.
+func orNull(cond Condition, field Field) Condition {
+	return Or(cond, fieldBoolOp(exp.IsOp, field, nil))
+}
+
+// negateCondition returns the logical opposite of a condition by inverting comparison
+// operators and applying De Morgan's laws to groups. Anything else is wrapped in Not.
+// Negated comparisons also match NULL, as they do in MongoDB.
+func negateCondition(cond Condition) Condition {
+	switch c := cond.(type) {
+	case BoolOp:
+		if negated, ok := negatedBoolOps[c.Op]; ok {
+			field := Field{Name: c.FieldName, TableAlias: c.TableAlias, Exp: c.Left}
+			isNullTest := c.Op == exp.IsOp || c.Op == exp.IsNotOp
+			c.Op = negated
+			if isNullTest {
+				return c
+			}
+			return orNull(c, field)
+		}
+
+	case RangeOp:
+		if c.Op == exp.NotBetweenOp {
+			c.Op = exp.BetweenOp
+		} else {
+			c.Op = exp.NotBetweenOp
+		}
+		return orNull(c, Field{Name: c.FieldName, TableAlias: c.TableAlias, Exp: c.Left})
+
+	case WhereGroup:
+		if c.Op == NotType {
+			return combineConditions(exp.AndType, c.Conditions)
+		}
+
+		conditions := make([]any, len(c.Conditions))
+		for i, child := range c.Conditions {
+			childCond, ok := child.(Condition)
+			if !ok {
+				return Not(c)
+			}
+			conditions[i] = negateCondition(childCond)
+		}
+
+		op := exp.AndType
+		if c.Op != exp.OrType {
+			op = exp.OrType
+		}
+		return WhereGroup{Op: op, Conditions: conditions}
+	}
+
+	return Not(cond)
+}
@@ -0,0 +1,493 @@
+package supersaiyan
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrUnsupportedOData is returned for OData constructs that cannot be translated to SQL.
+var ErrUnsupportedOData = errors.New("unsupported OData construct")
+
+// ApplyOData translates OData system query options into the builder.
+// $filter becomes WHERE conditions, $orderby becomes Sorts, $select becomes Fields,
+// and $top/$skip set the limit and offset. Property paths are resolved through fields,
+// which also acts as the allow-list.
+// Parameters without a "$" prefix are ignored; other system options are rejected, as is
+// $top=0, since a zero limit means no limit to the builder.
+//
+// Examples:
+//
+//	err := qb.ApplyOData(r.URL.Query(), FieldMap{
+//		"Name": F("username", WithTable("u")),
+//		"Age":  F("age", WithTable("u")),
+//	})
+func (qb *SQLBuilder) ApplyOData(values url.Values, fields FieldResolver) error {
+	for key, vals := range values {
+		if !strings.HasPrefix(key, "$") {
+			continue
+		}
+		if !slices.Contains([]string{"$filter", "$orderby", "$select", "$top", "$skip"}, key) {
+			return fmt.Errorf("%w: query option %s", ErrUnsupportedOData, key)
+		}
+		if len(vals) > 1 {
+			return fmt.Errorf("%w: query option %s given more than once", ErrInvalidParameter, key)
+		}
+	}
+
+	if s := values.Get("$filter"); s != "" {
+		cond, err := ParseODataFilter(s, fields)
+		if err != nil {
+			return fmt.Errorf("$filter: %w", err)
+		}
+		qb.Where(cond)
+	}
+
+	if s := values.Get("$orderby"); s != "" {
+		sorts, err := ParseODataOrderBy(s, fields)
+		if err != nil {
+			return fmt.Errorf("$orderby: %w", err)
+		}
+		qb.OrderBy(sorts...)
+	}
+
+	if s := values.Get("$select"); s != "" {
+		selected, err := parseODataSelect(s, fields)
+		if err != nil {
+			return fmt.Errorf("$select: %w", err)
+		}
+		qb.WithFields(selected...)
+	}
+
+	if s := values.Get("$top"); s != "" {
+		top, err := strconv.ParseUint(s, 10, 0)
+		if err != nil || top == 0 {
+			return fmt.Errorf("%w: $top must be a positive integer", ErrInvalidParameter)
+		}
+		qb.Limit(uint(top))
+	}
+
+	if s := values.Get("$skip"); s != "" {
+		skip, err := strconv.ParseUint(s, 10, 0)
+		if err != nil {
+			return fmt.Errorf("%w: $skip must be a non-negative integer", ErrInvalidParameter)
+		}
+		qb.Offset(uint(skip))
+	}
+
+	return nil
+}
+
+// ParseODataOrderBy parses an OData $orderby value such as "Name desc, Age" into Sorts.
+func ParseODataOrderBy(s string, fields FieldResolver) ([]Sort, error) {
+	var sorts []Sort
+
+	for _, term := range strings.Split(s, ",") {
+		parts := strings.Fields(term)
+		if len(parts) == 0 || len(parts) > 2 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, strings.TrimSpace(term))
+		}
+
+		order := exp.AscDir
+		if len(parts) == 2 {
+			switch strings.ToLower(parts[1]) {
+			case "asc":
+			case "desc":
+				order = exp.DescSortDir
+			default:
+				return nil, fmt.Errorf("%w: unknown direction %q", ErrInvalidSort, parts[1])
+			}
+		}
+
+		field, err := lookupField(fields, parts[0])
+		if err != nil {
+			return nil, err
+		}
+		sorts = append(sorts, sortOnField(field, order))
+	}
+
+	return sorts, nil
+}
+
+// parseODataSelect resolves a $select list into Fields.
+// Mapped columns are aliased to their public name so results keep the names clients asked for.
+func parseODataSelect(s string, fields FieldResolver) ([]Field, error) {
+	var selected []Field
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" {
+			return nil, nil
+		}
+
+		field, err := lookupField(fields, name)
+		if err != nil {
+			return nil, err
+		}
+		// Keep the public name when the field map renames the column
+		if !field.aliased() && field.Name != fieldFromPath(name).Name {
+			field.FieldAlias = name
+		}
+		selected = append(selected, field)
+	}
+
+	return selected, nil
+}
+
+// ParseODataFilter parses an OData $filter expression into a Condition tree.
+// Supported: eq, ne, gt, ge, lt, le, in, and, or, not, parentheses, null comparisons, and
+// the contains/startswith/endswith functions. Arithmetic, lambda operators and other
+// functions are rejected with ErrUnsupportedOData.
+//
+// Examples:
+//
+//	ParseODataFilter("Status eq 'active' and (Age ge 18 or Role in ('admin'))", AllowAnyField())
+//	ParseODataFilter("startswith(Name, 'Jo') and CreatedAt gt 2024-01-01", fields)
+func ParseODataFilter(s string, fields FieldResolver) (Condition, error) {
+	tokens, err := tokenizeOData(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &odataParser{tokenCursor: tokenCursor{tokens: tokens}, fields: fields}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+
+	return cond, nil
+}
+
+// odataComparisons maps OData comparison operators to goqu boolean operations.
+var odataComparisons = map[string]exp.BooleanOperation{
+	"eq": exp.EqOp,
+	"ne": exp.NeqOp,
+	"gt": exp.GtOp,
+	"ge": exp.GteOp,
+	"lt": exp.LtOp,
+	"le": exp.LteOp,
+}
+
+// odataUnsupportedOperators are valid OData operators that have no translation.
+var odataUnsupportedOperators = []string{"add", "sub", "mul", "div", "divby", "mod", "has"}
+
+// odataLikeFunctions maps OData string functions to the LIKE pattern they produce.
+var odataLikeFunctions = map[string]func(string) string{
+	"contains":   func(s string) string { return "%" + escapeLikePattern(s) + "%" },
+	"startswith": func(s string) string { return escapeLikePattern(s) + "%" },
+	"endswith":   func(s string) string { return "%" + escapeLikePattern(s) },
+}
+
+// tokenizeOData splits an OData $filter expression into tokens.
+// Unquoted literals starting with a digit (numbers, dates, timestamps) become number tokens.
+func tokenizeOData(s string) ([]filterToken, error) {
+	runes := []rune(s)
+	var tokens []filterToken
+
+	emit := func(kind filterTokenKind, text string, column int) {
+		tokens = append(tokens, filterToken{kind: kind, text: text, column: column})
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			emit(filterTokenLParen, "(", column)
+			i++
+		case r == ')':
+			emit(filterTokenRParen, ")", column)
+			i++
+		case r == ',':
+			emit(filterTokenComma, ",", column)
+			i++
+		case r == '\'':
+			text, end, err := lexQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			emit(filterTokenString, text, column)
+			i = end
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && isODataLiteralRune(runes[i]) {
+				i++
+			}
+			emit(filterTokenNumber, string(runes[start:i]), column)
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) &&
+				(isFilterIdentRune(runes[i]) || runes[i] == '/' || runes[i] == '$') {
+				i++
+			}
+			text := string(runes[start:i])
+			if strings.HasSuffix(text, "/any") || strings.HasSuffix(text, "/all") {
+				return nil, &SyntaxError{
+					Column: column,
+					Msg:    fmt.Sprintf("%s: lambda operator in %s", ErrUnsupportedOData, text),
+					Err:    ErrUnsupportedOData,
+				}
+			}
+			emit(filterTokenIdent, text, column)
+		default:
+			msg := fmt.Sprintf("unexpected character %q", r)
+			return nil, &SyntaxError{Column: column, Msg: msg}
+		}
+	}
+
+	return append(tokens, filterToken{kind: filterTokenEOF, column: len(runes) + 1}), nil
+}
+
+// isODataLiteralRune reports whether r may continue an unquoted numeric or temporal literal.
+func isODataLiteralRune(r rune) bool {
+	return unicode.IsDigit(r) || strings.ContainsRune(".-:+TZ", r)
+}
+
+// odataParser is a recursive descent parser for OData $filter expressions.
+type odataParser struct {
+	tokenCursor
+	fields FieldResolver
+}
+
+// unsupported returns a syntax error wrapping ErrUnsupportedOData.
+func (p *odataParser) unsupported(tok filterToken, what string) error {
+	return &SyntaxError{
+		Column: tok.column,
+		Msg:    fmt.Sprintf("%s: %s", ErrUnsupportedOData, what),
+		Err:    ErrUnsupportedOData,
+	}
+}
+
+// parseOr parses a sequence of AND groups joined by or.
+func (p *odataParser) parseOr() (Condition, error) {
+	return p.parseLogical(exp.OrType, "or", p.parseAnd)
+}
+
+// parseAnd parses a sequence of terms joined by and.
+func (p *odataParser) parseAnd() (Condition, error) {
+	return p.parseLogical(exp.AndType, "and", p.parseUnary)
+}
+
+// parseLogical parses operands separated by a logical keyword into a WhereGroup.
+func (p *odataParser) parseLogical(
+	op exp.ExpressionListType,
+	kw string,
+	operand func() (Condition, error),
+) (Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []any{first}
+	for p.peek().keyword(kw) {
+		p.next()
+		cond, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	if len(conditions) == 1 {
+		return first, nil
+	}
+	return WhereGroup{Op: op, Conditions: conditions}, nil
+}
+
+// parseUnary parses an optional not followed by a primary expression.
+// not before a string function becomes NOT LIKE; anything else is wrapped in a NOT group.
+func (p *odataParser) parseUnary() (Condition, error) {
+	if !p.peek().keyword("not") {
+		return p.parsePrimary()
+	}
+	p.next()
+
+	cond, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if like, ok := cond.(BoolOp); ok && like.Op == exp.LikeOp {
+		like.Op = exp.NotLikeOp
+		return like, nil
+	}
+	return Not(cond), nil
+}
+
+// parsePrimary parses a parenthesized expression, a function call or a comparison.
+func (p *odataParser) parsePrimary() (Condition, error) {
+	tok := p.peek()
+
+	if tok.kind == filterTokenLParen {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(filterTokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return cond, nil
+	}
+
+	if tok.kind == filterTokenIdent && p.tokens[p.pos+1].kind == filterTokenLParen {
+		return p.parseFunction()
+	}
+
+	return p.parseComparison()
+}
+
+// parseFunction parses contains/startswith/endswith(property, 'text') into a LIKE comparison.
+func (p *odataParser) parseFunction() (BoolOp, error) {
+	nameTok := p.next()
+	pattern, ok := odataLikeFunctions[strings.ToLower(nameTok.text)]
+	if !ok {
+		return BoolOp{}, p.unsupported(nameTok, fmt.Sprintf("function %s", nameTok.text))
+	}
+
+	if _, err := p.expect(filterTokenLParen, "("); err != nil {
+		return BoolOp{}, err
+	}
+	propTok, err := p.expect(filterTokenIdent, "property name")
+	if err != nil {
+		return BoolOp{}, err
+	}
+	field, err := resolveField(p.fields, propTok)
+	if err != nil {
+		return BoolOp{}, err
+	}
+	if _, err := p.expect(filterTokenComma, ","); err != nil {
+		return BoolOp{}, err
+	}
+	textTok, err := p.expect(filterTokenString, "string")
+	if err != nil {
+		return BoolOp{}, err
+	}
+	if _, err := p.expect(filterTokenRParen, ")"); err != nil {
+		return BoolOp{}, err
+	}
+
+	if next := p.peek(); next.kind == filterTokenIdent {
+		if _, isComparison := odataComparisons[next.text]; isComparison {
+			return BoolOp{}, p.unsupported(next, "comparing a function result")
+		}
+	}
+
+	return fieldBoolOp(exp.LikeOp, field, pattern(textTok.text)), nil
+}
+
+// parseComparison parses "property <op> value" and "property in (values)".
+func (p *odataParser) parseComparison() (Condition, error) {
+	propTok, err := p.expect(filterTokenIdent, "property name")
+	if err != nil {
+		return nil, err
+	}
+	field, err := resolveField(p.fields, propTok)
+	if err != nil {
+		return nil, err
+	}
+
+	opTok := p.next()
+	if opTok.keyword("in") {
+		if _, err := p.expect(filterTokenLParen, "( to start a list"); err != nil {
+			return nil, err
+		}
+		var values []any
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+
+			tok := p.next()
+			if tok.kind == filterTokenRParen {
+				break
+			}
+			if tok.kind != filterTokenComma {
+				return nil, p.errorf(tok, "expected , or ) in list, got %s", tok)
+			}
+		}
+		return fieldBoolOp(exp.InOp, field, values), nil
+	}
+
+	op, ok := odataComparisons[opTok.text]
+	if !ok || opTok.kind != filterTokenIdent {
+		if slices.Contains(odataUnsupportedOperators, opTok.text) {
+			return nil, p.unsupported(opTok, fmt.Sprintf("operator %s", opTok.text))
+		}
+		return nil, p.errorf(opTok, "expected comparison operator, got %s", opTok)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); slices.Contains(odataUnsupportedOperators, next.text) {
+		return nil, p.unsupported(next, fmt.Sprintf("operator %s", next.text))
+	}
+
+	if value == nil {
+		switch op {
+		case exp.EqOp:
+			op = exp.IsOp
+		case exp.NeqOp:
+			op = exp.IsNotOp
+		default:
+			return nil, p.errorf(opTok, "operator %s cannot be used with null", opTok.text)
+		}
+	}
+
+	return fieldBoolOp(op, field, value), nil
+}
+
+// parseValue parses a literal or a property reference on the right-hand side of a comparison.
+func (p *odataParser) parseValue() (any, error) {
+	tok := p.next()
+
+	switch {
+	case tok.kind == filterTokenString:
+		return tok.text, nil
+	case tok.kind == filterTokenNumber:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(tok.text, 64); err == nil {
+			return f, nil
+		}
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if t, err := time.Parse(layout, tok.text); err == nil {
+				return t, nil
+			}
+		}
+		return nil, p.errorf(tok, "invalid literal %s", tok)
+	case tok.keyword("null"):
+		return nil, nil
+	case tok.keyword("true"):
+		return true, nil
+	case tok.keyword("false"):
+		return false, nil
+	case tok.kind == filterTokenIdent:
+		if p.peek().kind == filterTokenLParen {
+			return nil, p.unsupported(tok, fmt.Sprintf("function %s", tok.text))
+		}
+		return resolveField(p.fields, tok)
+	default:
+		return nil, p.errorf(tok, "expected value, got %s", tok)
+	}
+}
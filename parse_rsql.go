@@ -0,0 +1,325 @@
+package supersaiyan
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// rsqlComparisons maps RSQL/FIQL comparison operators to goqu boolean operations.
+// =like= and =ilike= are common extensions; =isnull= takes true or false.
+var rsqlComparisons = map[string]exp.BooleanOperation{
+	"==":       exp.EqOp,
+	"!=":       exp.NeqOp,
+	"=gt=":     exp.GtOp,
+	">":        exp.GtOp,
+	"=ge=":     exp.GteOp,
+	">=":       exp.GteOp,
+	"=lt=":     exp.LtOp,
+	"<":        exp.LtOp,
+	"=le=":     exp.LteOp,
+	"<=":       exp.LteOp,
+	"=in=":     exp.InOp,
+	"=out=":    exp.NotInOp,
+	"=like=":   exp.LikeOp,
+	"=ilike=":  exp.ILikeOp,
+	"=isnull=": exp.IsOp,
+}
+
+// ParseRSQL parses an RSQL/FIQL query into a Condition tree of BoolOp and WhereGroup.
+// ";" joins constraints with AND and "," with OR (AND binds tighter); parentheses group.
+// Arguments are unquoted words or single/double-quoted strings with backslash escapes.
+// Unquoted numeric arguments become numbers, and an unquoted argument to == or != that
+// contains "*" becomes a LIKE / NOT LIKE pattern with "*" as the wildcard.
+// Selectors are resolved through fields, which also acts as the allow-list.
+//
+// Examples:
+//
+//	ParseRSQL("name==John;age=gt=30,role=in=(admin,mod)", AllowAnyField())
+//	ParseRSQL(`title=="Hello, world";author==Jo*`, fields)
+func ParseRSQL(s string, fields FieldResolver) (Condition, error) {
+	p := &rsqlParser{input: []rune(s), fields: fields}
+
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return cond, nil
+}
+
+// rsqlParser is a recursive descent parser working directly on the input runes.
+type rsqlParser struct {
+	input  []rune
+	pos    int
+	fields FieldResolver
+}
+
+func (p *rsqlParser) errorf(format string, args ...any) error {
+	return &SyntaxError{Column: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *rsqlParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// consume skips whitespace and consumes r if it is the next rune.
+func (p *rsqlParser) consume(r rune) bool {
+	p.skipSpace()
+	if p.pos < len(p.input) && p.input[p.pos] == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// parseOr parses AND groups separated by ",".
+func (p *rsqlParser) parseOr() (Condition, error) {
+	return p.parseLogical(exp.OrType, ',', p.parseAnd)
+}
+
+// parseAnd parses constraints separated by ";".
+func (p *rsqlParser) parseAnd() (Condition, error) {
+	return p.parseLogical(exp.AndType, ';', p.parseConstraint)
+}
+
+// parseLogical parses operands separated by sep into a WhereGroup.
+func (p *rsqlParser) parseLogical(
+	op exp.ExpressionListType,
+	sep rune,
+	operand func() (Condition, error),
+) (Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []any{first}
+	for p.consume(sep) {
+		cond, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+
+	if len(conditions) == 1 {
+		return first, nil
+	}
+	return WhereGroup{Op: op, Conditions: conditions}, nil
+}
+
+// parseConstraint parses a parenthesized group or a single comparison.
+func (p *rsqlParser) parseConstraint() (Condition, error) {
+	if p.consume('(') {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(')') {
+			return nil, p.errorf("expected )")
+		}
+		return cond, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses "selector operator argument".
+func (p *rsqlParser) parseComparison() (Condition, error) {
+	p.skipSpace()
+	selectorPos := p.pos
+	selector := p.readUnreserved()
+	if selector == "" {
+		return nil, p.errorf("expected selector")
+	}
+
+	field, err := lookupField(p.fields, selector)
+	if err != nil {
+		return nil, &SyntaxError{Column: selectorPos + 1, Msg: err.Error(), Err: err}
+	}
+
+	p.skipSpace()
+	opPos := p.pos
+	opText := p.readOperator()
+	op, ok := rsqlComparisons[opText]
+	if !ok {
+		p.pos = opPos
+		if opText == "" {
+			return nil, p.errorf("expected comparison operator")
+		}
+		return nil, p.errorf("unknown comparison operator %q", opText)
+	}
+
+	switch op {
+	case exp.InOp, exp.NotInOp:
+		values, err := p.parseArgumentList()
+		if err != nil {
+			return nil, err
+		}
+		return fieldBoolOp(op, field, values), nil
+
+	case exp.IsOp:
+		p.skipSpace()
+		argPos := p.pos
+		arg, _, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+		isNull, err := strconv.ParseBool(arg)
+		if err != nil {
+			p.pos = argPos
+			return nil, p.errorf("=isnull= expects true or false")
+		}
+		if !isNull {
+			op = exp.IsNotOp
+		}
+		return fieldBoolOp(op, field, nil), nil
+	}
+
+	arg, quoted, err := p.parseArgument()
+	if err != nil {
+		return nil, err
+	}
+
+	if !quoted && strings.Contains(arg, "*") && (op == exp.EqOp || op == exp.NeqOp) {
+		pattern := strings.ReplaceAll(escapeLikePattern(arg), "*", "%")
+		if op == exp.NeqOp {
+			return fieldBoolOp(exp.NotLikeOp, field, pattern), nil
+		}
+		return fieldBoolOp(exp.LikeOp, field, pattern), nil
+	}
+
+	return fieldBoolOp(op, field, rsqlValue(arg, quoted)), nil
+}
+
+// parseArgumentList parses a parenthesized, comma-separated list of arguments.
+func (p *rsqlParser) parseArgumentList() ([]any, error) {
+	if !p.consume('(') {
+		return nil, p.errorf("expected ( to start a list")
+	}
+
+	var values []any
+	for {
+		arg, quoted, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, rsqlValue(arg, quoted))
+
+		if p.consume(')') {
+			return values, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected , or ) in list")
+		}
+	}
+}
+
+// parseArgument parses a quoted or unquoted argument and reports whether it was quoted.
+func (p *rsqlParser) parseArgument() (string, bool, error) {
+	p.skipSpace()
+	if p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\'') {
+		s, err := p.readQuoted()
+		return s, true, err
+	}
+
+	arg := p.readUnreserved()
+	if arg == "" {
+		return "", false, p.errorf("expected argument")
+	}
+	return arg, false, nil
+}
+
+// readQuoted reads a quoted string where a backslash escapes the next character.
+func (p *rsqlParser) readQuoted() (string, error) {
+	start := p.pos
+	quote := p.input[p.pos]
+	var sb strings.Builder
+
+	for p.pos++; p.pos < len(p.input); p.pos++ {
+		r := p.input[p.pos]
+		switch {
+		case r == '\\' && p.pos+1 < len(p.input):
+			p.pos++
+			sb.WriteRune(p.input[p.pos])
+		case r == quote:
+			p.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+// readUnreserved reads a run of characters that have no special meaning in RSQL.
+func (p *rsqlParser) readUnreserved() string {
+	start := p.pos
+	for p.pos < len(p.input) && !isRSQLReserved(p.input[p.pos]) {
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// readOperator reads a comparison operator: "==", "!=", "<", "<=", ">", ">=" or "=name=".
+func (p *rsqlParser) readOperator() string {
+	start := p.pos
+	if p.pos >= len(p.input) {
+		return ""
+	}
+
+	switch p.input[p.pos] {
+	case '<', '>', '!':
+		p.pos++
+		if p.pos < len(p.input) && p.input[p.pos] == '=' {
+			p.pos++
+		}
+	case '=':
+		p.pos++
+		for p.pos < len(p.input) && unicode.IsLetter(p.input[p.pos]) {
+			p.pos++
+		}
+		if p.pos < len(p.input) && p.input[p.pos] == '=' {
+			p.pos++
+		}
+	}
+
+	return string(p.input[start:p.pos])
+}
+
+// isRSQLReserved reports whether r is a reserved RSQL character.
+func isRSQLReserved(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`"'();,=!~<>`, r)
+}
+
+// rsqlNumberPattern matches the unquoted arguments read as numbers: plain decimals with an
+// optional exponent, so words like nan or Infinity and forms like 1_000 stay strings.
+var rsqlNumberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// rsqlValue converts an argument to a Go value; unquoted numbers become int64 or float64.
+func rsqlValue(arg string, quoted bool) any {
+	if quoted || !rsqlNumberPattern.MatchString(arg) {
+		return arg
+	}
+	if i, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(arg, 64); err == nil {
+		return f
+	}
+	return arg
+}
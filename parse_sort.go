@@ -0,0 +1,108 @@
+package supersaiyan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// ErrInvalidSort is returned when a sort string cannot be parsed.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// ParseSorts parses a REST-style sort string into Sorts.
+// Terms are separated by commas. A term is a public field name with an optional
+// "-" (descending) or "+" (ascending) prefix, or an optional ":asc" / ":desc" suffix.
+// Names are resolved through fields, which also acts as the allow-list.
+//
+// Examples:
+//
+//	ParseSorts("-created_at,username", AllowAnyField())
+//	ParseSorts("created_at:desc,+name", FieldMap{"created_at": F("created_at", WithTable("u"))})
+func ParseSorts(s string, fields FieldResolver) ([]Sort, error) {
+	var sorts []Sort
+	seen := make(map[string]bool)
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		name, order, err := parseSortTerm(term)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("%w: field %q is sorted more than once", ErrInvalidSort, name)
+		}
+		seen[name] = true
+
+		field, err := lookupField(fields, name)
+		if err != nil {
+			return nil, err
+		}
+
+		sorts = append(sorts, sortOnField(field, order))
+	}
+
+	return sorts, nil
+}
+
+// parseSortTerm splits a single sort term into its field name and direction.
+func parseSortTerm(term string) (string, exp.SortDirection, error) {
+	order := exp.AscDir
+	prefixed := false
+
+	switch term[0] {
+	case '-':
+		order = exp.DescSortDir
+		prefixed = true
+		term = term[1:]
+	case '+':
+		prefixed = true
+		term = term[1:]
+	}
+
+	if name, dir, ok := strings.Cut(term, ":"); ok {
+		if prefixed {
+			return "", order, fmt.Errorf(
+				"%w: %q uses both a prefix and a direction suffix",
+				ErrInvalidSort,
+				term,
+			)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(dir)) {
+		case "asc":
+			order = exp.AscDir
+		case "desc":
+			order = exp.DescSortDir
+		default:
+			return "", order, fmt.Errorf("%w: unknown direction %q", ErrInvalidSort, dir)
+		}
+		term = name
+	}
+
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return "", order, fmt.Errorf("%w: missing field name", ErrInvalidSort)
+	}
+
+	return term, order, nil
+}
+
+// sortOnField creates a Sort targeting the given Field.
+// Expression fields sort on their expression, alias-only fields on their alias.
+func sortOnField(f Field, order exp.SortDirection) Sort {
+	switch {
+	case f.Exp != nil:
+		return Sort{Exp: f.Exp, Order: order}
+	case f.Name == "" && f.aliased():
+		return Sort{FieldAlias: f.FieldAlias, Order: order}
+	default:
+		return Sort{Name: f.Name, TableAlias: f.TableAlias, Order: order}
+	}
+}
@@ -0,0 +1,336 @@
+package supersaiyan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownRelation is returned when a query joins a relation that is not declared in its
+	// RelationMap.
+	ErrUnknownRelation = errors.New("unknown relation")
+	// ErrAmbiguousRelation is returned when an alias a query references can be joined along
+	// more than one equally short path of its RelationMap.
+	ErrAmbiguousRelation = errors.New("ambiguous relation")
+)
+
+// RelationMap declares joins once under a name, for queries to join by name with Joins.
+// A relation joins the main table of the query naming it, so Keys refer to that table's
+// columns, and it brings its nested relations along. It decodes from JSON or YAML documents
+// mapping names to relations.
+//
+// The map is also the graph joins are inferred from: an alias the query's fields, conditions,
+// sorts or grouping reference without joining it is joined through the fewest relations,
+// counting the relations that provide the aliases a relation's Keys ("alias.column") and On
+// conditions need. Aliases no relation provides are left alone.
+//
+// Examples:
+//
+//	relations := RelationMap{
+//		"orders": {
+//			JoinType: exp.LeftJoinType,
+//			Keys:     map[string]string{"user_id": "id"},
+//			Table:    Table{Name: "orders", Alias: "o"},
+//		},
+//	}
+//	New("postgres", "users", "u").WithRelations(relations).JoinNamed("orders")
+type RelationMap map[string]Relation
+
+// ParseRelations decodes a RelationMap from a YAML (or JSON) document mapping names to
+// relations. Every relation, nested ones included, needs a table alias to be joined under.
+//
+// Examples:
+//
+//	relations, err := ParseRelations(data) // orders: {joinType: LEFT, keys: {user_id: id}, ...}
+func ParseRelations(data []byte) (RelationMap, error) {
+	var relations RelationMap
+	if err := yaml.Unmarshal(data, &relations); err != nil {
+		return nil, err
+	}
+	for _, name := range relations.names() {
+		for alias := range joinedAliases(relations[name].Table) {
+			if alias == "" {
+				return nil, fmt.Errorf(
+					"%w: relation %q joins a table without an alias",
+					ErrInvalidJoin,
+					name,
+				)
+			}
+		}
+	}
+	return relations, nil
+}
+
+// resolve returns the relations registered under names, in order, for a query on main.
+// Names whose table alias is already joined to the same table, by the query or an earlier
+// name, are skipped; an alias joined to a different table is an error.
+func (rm RelationMap) resolve(
+	names []string,
+	main Table,
+	joined map[string]bool,
+) ([]Relation, error) {
+	tables := joinedTables(main)
+	relations := make([]Relation, 0, len(names))
+	for _, name := range names {
+		relation, ok := rm[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownRelation, name)
+		}
+		if existing, ok := tables[relation.Table.Alias]; ok {
+			if !existing.sameSource(relation.Table) {
+				return nil, fmt.Errorf(
+					"%w: relation %q joins %q as %q, which is already joined to %q",
+					ErrInvalidJoin,
+					name,
+					relation.Table.Name,
+					relation.Table.Alias,
+					existing.Name,
+				)
+			}
+			continue
+		}
+		for alias, table := range joinedTables(relation.Table) {
+			joined[alias] = true
+			tables[alias] = table
+		}
+		relations = append(relations, relation)
+	}
+	return relations, nil
+}
+
+// joinedAliases returns the aliases of the table and of every table joined to it.
+func joinedAliases(table Table) map[string]bool {
+	aliases := map[string]bool{}
+	for alias := range joinedTables(table) {
+		aliases[alias] = true
+	}
+	return aliases
+}
+
+// joinedTables returns the table and every table joined to it by alias.
+func joinedTables(table Table) map[string]Table {
+	tables := map[string]Table{table.Alias: table}
+	for _, relation := range table.Relations {
+		for alias, joined := range joinedTables(relation.Table) {
+			tables[alias] = joined
+		}
+	}
+	return tables
+}
+
+// infer returns the relations joining the referenced aliases missing from joined, adding
+// their aliases to joined.
+func (rm RelationMap) infer(referenced []string, joined map[string]bool) ([]Relation, error) {
+	var relations []Relation
+	for _, alias := range referenced {
+		names, found, err := rm.path(alias, joined, map[string]bool{})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		for _, name := range names {
+			relation := rm[name]
+			for alias := range joinedAliases(relation.Table) {
+				joined[alias] = true
+			}
+			relations = append(relations, relation)
+		}
+	}
+	return relations, nil
+}
+
+// path returns the names of the fewest relations joining alias, in join order, and whether
+// there is such a path. Relations being visited are skipped to break cycles.
+func (rm RelationMap) path(
+	alias string,
+	joined, visiting map[string]bool,
+) ([]string, bool, error) {
+	if joined[alias] {
+		return nil, true, nil
+	}
+
+	var best []string
+	var bestName, tiedName string
+	for _, name := range rm.names() {
+		relation := rm[name]
+		provides := joinedAliases(relation.Table)
+		if !provides[alias] || visiting[name] {
+			continue
+		}
+
+		visiting[name] = true
+		names, ok, err := rm.dependencies(relation, provides, joined, visiting)
+		delete(visiting, name)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+
+		names = append(names, name)
+		switch {
+		case best == nil || len(names) < len(best):
+			best, bestName, tiedName = names, name, ""
+		case len(names) == len(best):
+			tiedName = name
+		}
+	}
+
+	if tiedName != "" {
+		return nil, false, fmt.Errorf(
+			"%w: %q is joined by both %q and %q",
+			ErrAmbiguousRelation,
+			alias,
+			bestName,
+			tiedName,
+		)
+	}
+	return best, best != nil, nil
+}
+
+// dependencies returns the names of the relations to join before relation, for the aliases
+// its Keys and On conditions reference outside the tables it provides.
+func (rm RelationMap) dependencies(
+	relation Relation,
+	provides, joined, visiting map[string]bool,
+) ([]string, bool, error) {
+	refs := aliasRefs{seen: map[string]bool{}}
+	refs.walk(relation.On)
+	columns := make([]string, 0, len(relation.Keys))
+	for column := range relation.Keys {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		if alias, _, qualified := strings.Cut(relation.Keys[column], "."); qualified {
+			refs.add(alias)
+		}
+	}
+
+	var names []string
+	for _, alias := range refs.aliases {
+		if provides[alias] {
+			continue
+		}
+		path, ok, err := rm.path(alias, joined, visiting)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		for _, name := range path {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, true, nil
+}
+
+// names returns the relation names in sorted order, so inference does not depend on map order.
+func (rm RelationMap) names() []string {
+	names := make([]string, 0, len(rm))
+	for name := range rm {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aliasRefs collects the table aliases expressions reference, in order of first use.
+type aliasRefs struct {
+	seen    map[string]bool
+	aliases []string
+}
+
+// add records a referenced alias.
+func (r *aliasRefs) add(alias string) {
+	if alias != "" && !r.seen[alias] {
+		r.seen[alias] = true
+		r.aliases = append(r.aliases, alias)
+	}
+}
+
+// walk records the aliases referenced by v and the expressions nested in it. Subqueries are
+// not entered: they resolve their own aliases.
+func (r *aliasRefs) walk(v any) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return
+		}
+		v = rv.Elem().Interface()
+	}
+
+	switch v := v.(type) {
+	case Field:
+		if v.Exp != nil {
+			r.walk(v.Exp)
+		} else if v.Name != "" {
+			r.add(v.TableAlias)
+		}
+	case Sort:
+		if v.Exp != nil {
+			r.walk(v.Exp)
+		} else if v.Name != "" {
+			r.add(v.TableAlias)
+		}
+	case BoolOp:
+		if v.Left != nil {
+			r.walk(v.Left)
+		} else {
+			r.add(v.TableAlias)
+		}
+		r.walk(v.Value)
+	case RangeOp:
+		if v.Left != nil {
+			r.walk(v.Left)
+		} else {
+			r.add(v.TableAlias)
+		}
+		r.walk(v.Start)
+		r.walk(v.End)
+	case WhereGroup:
+		r.walk(v.Conditions)
+	case Search:
+		for _, column := range v.Columns {
+			r.walk(column)
+		}
+	case SearchRank:
+		r.walk(v.Search)
+	case Literal:
+		r.walk(v.Args)
+	case Case:
+		for _, when := range v.Conditions {
+			r.walk(when.When)
+			r.walk(when.Then)
+		}
+		r.walk(v.Else)
+	case Func:
+		r.walk(v.Args)
+		r.walk(v.Filter)
+	case Arith:
+		r.walk(v.Operands)
+	case Cast:
+		r.walk(v.Exp)
+	case DateFunc:
+		r.walk(v.Exp)
+	case JSONPath:
+		r.walk(v.Exp)
+	case Coalesce:
+		for _, f := range v.Fields {
+			r.walk(f)
+		}
+		r.walk(v.DefaultValue)
+	case []any:
+		for _, item := range v {
+			r.walk(item)
+		}
+	}
+}
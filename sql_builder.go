@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
@@ -29,14 +30,20 @@ func (qb *SQLBuilder) applyLimitOffset(ds *goqu.SelectDataset) *goqu.SelectDatas
 // It supports SELECT, INSERT, UPDATE, and DELETE operations with joins, filters, and sorting.
 // All queries use prepared statements by default for security.
 type SQLBuilder struct {
-	Dialect string  `json:"dialect"           yaml:"dialect"`
-	Fields  []Field `json:"fields,omitempty"  yaml:"fields,omitempty"`
-	Table   Table   `json:"table"             yaml:"table"`
-	Wheres  []any   `json:"wheres,omitempty"  yaml:"wheres,omitempty"` // Should contain Condition types (BoolOp, RangeOp, WhereGroup)
-	Sorts   []Sort  `json:"sorts,omitempty"   yaml:"sorts,omitempty"`
-	GroupBy []Field `json:"groupBy,omitempty" yaml:"groupBy,omitempty"`
+	Dialect string   `json:"dialect"           yaml:"dialect"`
+	Fields  []Field  `json:"fields,omitempty"  yaml:"fields,omitempty"`
+	Table   Table    `json:"table"             yaml:"table"`
+	Joins   []string `json:"join,omitempty"    yaml:"join,omitempty"`
+	Wheres  []any    `json:"wheres,omitempty"  yaml:"wheres,omitempty"` // Should contain Condition types (BoolOp, RangeOp, WhereGroup)
+	Sorts   []Sort   `json:"sorts,omitempty"   yaml:"sorts,omitempty"`
+	GroupBy []Field  `json:"groupBy,omitempty" yaml:"groupBy,omitempty"`
 	limit   uint
 	offset  uint
+	clock   func() time.Time
+	// relationMap holds the relations Joins name and joins are inferred from
+	relationMap RelationMap
+	// outerAliases are the aliases in scope of the query this one is a subquery of
+	outerAliases map[string]bool
 }
 
 // New creates a new SQLBuilder with the specified dialect and table.
@@ -52,6 +59,49 @@ func New(dialect string, tableName string, tableAlias string) *SQLBuilder {
 	}
 }
 
+// WithClock sets the clock RelativeTime values such as "now-30d" resolve against.
+// It defaults to time.Now; tests can pin it to a fixed time.
+func (qb *SQLBuilder) WithClock(clock func() time.Time) *SQLBuilder {
+	qb.clock = clock
+	return qb
+}
+
+// renderContext creates the render context for one query.
+func (qb *SQLBuilder) renderContext() *renderContext {
+	return &renderContext{dialect: qb.Dialect, clock: qb.clock, relations: qb.relationMap}
+}
+
+// WithSchema qualifies the main table with a schema.
+func (qb *SQLBuilder) WithSchema(schema string) *SQLBuilder {
+	qb.Table.Schema = schema
+	return qb
+}
+
+// WithDatabase qualifies the main table with a database and schema, for cross-database queries
+// on SQL Server.
+func (qb *SQLBuilder) WithDatabase(database, schema string) *SQLBuilder {
+	qb.Table.Database = database
+	qb.Table.Schema = schema
+	return qb
+}
+
+// WithRelations sets the named relations that Joins, and subqueries without their own, can
+// refer to.
+func (qb *SQLBuilder) WithRelations(relations RelationMap) *SQLBuilder {
+	qb.relationMap = relations
+	return qb
+}
+
+// JoinNamed joins relations registered in the RelationMap by name.
+//
+// Examples:
+//
+//	qb.WithRelations(relations).JoinNamed("orders", "profile")
+func (qb *SQLBuilder) JoinNamed(names ...string) *SQLBuilder {
+	qb.Joins = append(qb.Joins, names...)
+	return qb
+}
+
 // WithFields adds multiple fields to select.
 func (qb *SQLBuilder) WithFields(fields ...Field) *SQLBuilder {
 	qb.Fields = append(qb.Fields, fields...)
@@ -107,38 +157,120 @@ func (qb *SQLBuilder) RightJoin(tableName, tableAlias string, on ...Condition) *
 	return qb.Join(exp.RightJoinType, Table{Name: tableName, Alias: tableAlias}, on...)
 }
 
+// JoinUsing adds a join on the columns named the same in both tables, rendered as USING or,
+// on SQL Server, as equality of the columns.
+//
+// Examples:
+//
+//	qb.JoinUsing(exp.InnerJoinType, "accounts", "a", "tenant_id", "user_id")
+//	// INNER JOIN "accounts" AS "a" USING ("tenant_id", "user_id")
+func (qb *SQLBuilder) JoinUsing(
+	joinType exp.JoinType,
+	tableName, tableAlias string,
+	columns ...string,
+) *SQLBuilder {
+	qb.Join(joinType, Table{Name: tableName, Alias: tableAlias})
+	qb.Table.Relations[len(qb.Table.Relations)-1].Using = columns
+	return qb
+}
+
+// JoinKeys adds a join on joined columns equal to columns of the main table, keyed by the
+// joined column.
+//
+// Examples:
+//
+//	qb.JoinKeys(exp.LeftJoinType, "orders", "o", map[string]string{
+//		"user_id":   "id",
+//		"tenant_id": "tenant_id",
+//	})
+//	// LEFT JOIN "orders" AS "o"
+//	// ON (("o"."tenant_id" = "u"."tenant_id") AND ("o"."user_id" = "u"."id"))
+func (qb *SQLBuilder) JoinKeys(
+	joinType exp.JoinType,
+	tableName, tableAlias string,
+	keys map[string]string,
+) *SQLBuilder {
+	qb.Join(joinType, Table{Name: tableName, Alias: tableAlias})
+	qb.Table.Relations[len(qb.Table.Relations)-1].Keys = keys
+	return qb
+}
+
+// FullJoin adds a FULL OUTER JOIN. It is not available on MySQL.
+func (qb *SQLBuilder) FullJoin(tableName, tableAlias string, on ...Condition) *SQLBuilder {
+	return qb.Join(exp.FullOuterJoinType, Table{Name: tableName, Alias: tableAlias}, on...)
+}
+
+// CrossJoin adds a CROSS JOIN, which takes no conditions.
+func (qb *SQLBuilder) CrossJoin(tableName, tableAlias string) *SQLBuilder {
+	return qb.Join(exp.CrossJoinType, Table{Name: tableName, Alias: tableAlias})
+}
+
+// NaturalJoin adds a NATURAL JOIN on the columns both tables share. It is not available on
+// SQL Server.
+func (qb *SQLBuilder) NaturalJoin(tableName, tableAlias string) *SQLBuilder {
+	return qb.Join(exp.NaturalJoinType, Table{Name: tableName, Alias: tableAlias})
+}
+
+// LateralJoin joins a subquery that can reference the tables joined before it, joined ON TRUE
+// when no conditions are given. It is available on PostgreSQL and MySQL.
+//
+// Examples:
+//
+//	latest := New("postgres", "orders", "o").
+//		Where(Eq("user_id", "o", F("id", WithTable("u")))).
+//		OrderBy(Desc("created_at", "o")).
+//		Limit(3)
+//	qb.LateralJoin(exp.LeftJoinType, latest, "recent")
+//	// LEFT JOIN LATERAL (SELECT * FROM "orders" AS "o" WHERE ... LIMIT ?) AS "recent" ON TRUE
+func (qb *SQLBuilder) LateralJoin(
+	joinType exp.JoinType,
+	query *SQLBuilder,
+	alias string,
+	on ...Condition,
+) *SQLBuilder {
+	qb.Join(joinType, Table{Alias: alias}, on...)
+	relation := &qb.Table.Relations[len(qb.Table.Relations)-1]
+	relation.Lateral = true
+	relation.Query = query
+	return qb
+}
+
 // mainSelect builds the base SELECT query with joins, fields, filters, sorting, and grouping.
+// Rendering errors are attached to the dataset and returned by ToSQL.
 func (qb *SQLBuilder) mainSelect() *goqu.SelectDataset {
-	ds := goqu.From(goqu.T(qb.Table.Name).As(qb.Table.Alias)).WithDialect(qb.Dialect)
+	rc := qb.renderContext()
+	if err := qb.Table.validate(qb.Dialect); err != nil {
+		rc.fail(err)
+	}
+	ds := goqu.From(qb.Table.identifier().As(qb.Table.Alias)).WithDialect(qb.Dialect)
 
-	// Apply joins
+	// Apply joins, then the named relations and those inferred from referenced aliases
 	for _, rel := range qb.Table.Relations {
-		ds = rel.join(ds)
+		ds = rel.join(rc, ds, qb.Table.Alias)
+	}
+	for _, rel := range qb.namedRelations(rc) {
+		ds = rel.join(rc, ds, qb.Table.Alias)
 	}
 
 	// Apply field selection
 	if len(qb.Fields) > 0 {
 		selects := make([]any, len(qb.Fields))
 		for i, f := range qb.Fields {
-			selects[i] = f.expression()
+			selects[i] = f.expression(rc)
 		}
 		ds = ds.Select(selects...)
 	}
 
 	// Apply WHERE conditions
 	if len(qb.Wheres) > 0 {
-		expressions := make([]exp.Expression, len(qb.Wheres))
-		for i, w := range qb.Wheres {
-			expressions[i] = handleAny(w)
-		}
-		ds = ds.Where(expressions...)
+		ds = ds.Where(qb.whereExpressions(rc)...)
 	}
 
 	// Apply sorting
 	if len(qb.Sorts) > 0 {
-		orders := make([]exp.OrderedExpression, len(qb.Sorts))
-		for i, s := range qb.Sorts {
-			orders[i] = s.expression()
+		orders := make([]exp.OrderedExpression, 0, len(qb.Sorts))
+		for _, s := range qb.Sorts {
+			orders = append(orders, s.expressions(rc, qb.Fields)...)
 		}
 		ds = ds.Order(orders...)
 	}
@@ -155,14 +287,73 @@ func (qb *SQLBuilder) mainSelect() *goqu.SelectDataset {
 				}
 			} else if g.aliased() {
 				groupFields[i] = g.FieldAlias
+			} else if g.Exp != nil {
+				groupFields[i] = handleAny(rc, g.Exp)
 			}
 		}
 		ds = ds.GroupBy(groupFields...)
 	}
 
+	if rc.err != nil {
+		ds = ds.SetError(rc.err)
+	}
+
 	return ds
 }
 
+// namedRelations returns the relations joined by name, followed by those joining aliases the
+// query references without joining them. It records the aliases visible to subqueries.
+func (qb *SQLBuilder) namedRelations(rc *renderContext) []Relation {
+	joined := joinedAliases(qb.Table)
+	named, err := rc.relations.resolve(qb.Joins, qb.Table, joined)
+	if err != nil {
+		rc.fail(err)
+	}
+
+	// Aliases of the outer query are in scope too, so correlated references are not joined
+	for alias := range qb.outerAliases {
+		joined[alias] = true
+	}
+	inferred, err := rc.relations.infer(qb.referencedAliases(), joined)
+	if err != nil {
+		rc.fail(err)
+	}
+
+	rc.aliases = joined
+	return append(named, inferred...)
+}
+
+// referencedAliases returns the table aliases used by the fields, conditions, sorts and
+// grouping of the query, in order of first use.
+func (qb *SQLBuilder) referencedAliases() []string {
+	refs := aliasRefs{seen: map[string]bool{}}
+	for _, f := range qb.Fields {
+		refs.walk(f)
+	}
+	for _, w := range qb.Wheres {
+		refs.walk(w)
+	}
+	for _, s := range qb.Sorts {
+		refs.walk(s)
+	}
+	for _, g := range qb.GroupBy {
+		refs.walk(g)
+	}
+	return refs.aliases
+}
+
+// whereExpressions converts the WHERE conditions to goqu expressions.
+// Empty groups are skipped; values that cannot be conditions fail the query.
+func (qb *SQLBuilder) whereExpressions(rc *renderContext) []exp.Expression {
+	expressions := make([]exp.Expression, 0, len(qb.Wheres))
+	for _, w := range qb.Wheres {
+		if expr := conditionExpression(rc, w); expr != nil {
+			expressions = append(expressions, expr)
+		}
+	}
+	return expressions
+}
+
 // Count generates a COUNT query and returns the SQL string, arguments, and any error.
 // Uses prepared statements by default for security.
 func (qb *SQLBuilder) Count() (string, []any, error) {
@@ -202,7 +393,11 @@ func (qb *SQLBuilder) Offset(offset uint) *SQLBuilder {
 // Add generates an INSERT query and returns the SQL string, arguments, and any error.
 // Uses prepared statements by default for security.
 func (qb *SQLBuilder) Add(entry map[string]any) (string, []any, error) {
-	ds := goqu.Insert(goqu.T(qb.Table.Name)).
+	if err := qb.Table.validate(qb.Dialect); err != nil {
+		return "", nil, err
+	}
+
+	ds := goqu.Insert(qb.Table.identifier()).
 		WithDialect(qb.Dialect).
 		Rows(goqu.Record(entry)).
 		Prepared(true)
@@ -218,14 +413,22 @@ func (qb *SQLBuilder) Edit(entry map[string]any) (string, []any, error) {
 		return "", nil, ErrMissingWhereCondition
 	}
 
-	ds := goqu.Update(goqu.T(qb.Table.Name)).WithDialect(qb.Dialect)
+	if err := qb.Table.validate(qb.Dialect); err != nil {
+		return "", nil, err
+	}
+
+	rc := qb.renderContext()
+	ds := goqu.Update(qb.Table.identifier()).WithDialect(qb.Dialect)
 
 	// Apply WHERE conditions from builder
-	expressions := make([]exp.Expression, len(qb.Wheres))
-	for i, w := range qb.Wheres {
-		expressions[i] = handleAny(w)
+	wheres := qb.whereExpressions(rc)
+	if rc.err != nil {
+		return "", nil, rc.err
+	}
+	if len(wheres) == 0 {
+		return "", nil, ErrMissingWhereCondition
 	}
-	ds = ds.Where(expressions...)
+	ds = ds.Where(wheres...)
 
 	ds = ds.Set(goqu.Record(entry)).Prepared(true)
 
@@ -240,14 +443,22 @@ func (qb *SQLBuilder) Delete() (string, []any, error) {
 		return "", nil, ErrMissingWhereCondition
 	}
 
-	ds := goqu.Delete(goqu.T(qb.Table.Name)).WithDialect(qb.Dialect)
+	if err := qb.Table.validate(qb.Dialect); err != nil {
+		return "", nil, err
+	}
+
+	rc := qb.renderContext()
+	ds := goqu.Delete(qb.Table.identifier()).WithDialect(qb.Dialect)
 
 	// Apply WHERE conditions from builder
-	expressions := make([]exp.Expression, len(qb.Wheres))
-	for i, w := range qb.Wheres {
-		expressions[i] = handleAny(w)
+	wheres := qb.whereExpressions(rc)
+	if rc.err != nil {
+		return "", nil, rc.err
+	}
+	if len(wheres) == 0 {
+		return "", nil, ErrMissingWhereCondition
 	}
-	ds = ds.Where(expressions...)
+	ds = ds.Where(wheres...)
 
 	ds = ds.Prepared(true)
 
@@ -289,6 +500,7 @@ func (qb *SQLBuilder) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Dialect string                   `yaml:"dialect"`
 		Fields  []Field                  `yaml:"fields,omitempty"`
 		Table   Table                    `yaml:"table"`
+		Joins   []string                 `yaml:"join,omitempty"`
 		Wheres  []map[string]interface{} `yaml:"wheres,omitempty"`
 		Sorts   []Sort                   `yaml:"sorts,omitempty"`
 		GroupBy []Field                  `yaml:"groupBy,omitempty"`
@@ -301,6 +513,7 @@ func (qb *SQLBuilder) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	qb.Dialect = aux.Dialect
 	qb.Fields = aux.Fields
 	qb.Table = aux.Table
+	qb.Joins = aux.Joins
 	qb.Sorts = aux.Sorts
 	qb.GroupBy = aux.GroupBy
 
@@ -2,7 +2,9 @@ package supersaiyan
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/doug-martin/goqu/v9"
@@ -10,91 +12,283 @@ import (
 )
 
 // Table represents a database table with its alias and relations (joins).
+// Schema qualifies the name, and Database the schema, for cross-database queries on
+// SQL Server. Each part is quoted separately, so "analytics.events" is Schema "analytics"
+// and Name "events".
 type Table struct {
 	Name      string     `json:"name"                yaml:"name"                validate:"required"`
+	Schema    string     `json:"schema,omitempty"    yaml:"schema,omitempty"`
+	Database  string     `json:"database,omitempty"  yaml:"database,omitempty"`
 	Alias     string     `json:"alias"               yaml:"alias"               validate:"required"`
 	Relations []Relation `json:"relations,omitempty" yaml:"relations,omitempty"`
 }
 
-// Relation represents a JOIN relationship between tables.
-// The On field should contain Condition types (BoolOp, RangeOp, or WhereGroup).
-type Relation struct {
-	JoinType exp.JoinType `json:"joinType"     yaml:"joinType"`
-	On       []any        `json:"on,omitempty" yaml:"on,omitempty"` // Should contain Condition types
-	Table    Table        `json:"table"        yaml:"table"`
+// ErrInvalidTable is returned when a table name cannot be qualified as given.
+var ErrInvalidTable = errors.New("invalid table")
+
+// identifier returns the qualified, unaliased table name.
+func (t Table) identifier() exp.IdentifierExpression {
+	switch {
+	case t.Database != "":
+		// goqu identifiers have three parts, used here as database, schema and table
+		return exp.NewIdentifierExpression(t.Database, t.Schema, t.Name)
+	case t.Schema != "":
+		return goqu.S(t.Schema).Table(t.Name)
+	default:
+		return goqu.T(t.Name)
+	}
 }
 
-// join applies this relation as a JOIN clause to the given dataset.
-// It recursively applies nested relations (joins on joined tables).
-func (r Relation) join(ds *goqu.SelectDataset) *goqu.SelectDataset {
-	onConds := make([]exp.Expression, 0, len(r.On))
-	for _, on := range r.On {
-		// Use type assertion with Condition interface for better type safety
-		if cond, ok := on.(Condition); ok {
-			onConds = append(onConds, cond.toExpression())
-			continue
-		}
+// sameSource reports whether both tables name the same qualified table.
+func (t Table) sameSource(other Table) bool {
+	return t.Name == other.Name && t.Schema == other.Schema && t.Database == other.Database
+}
 
-		// Fallback to old behavior for backward compatibility
-		var expr exp.Expression
-		switch v := on.(type) {
-		case BoolOp:
-			expr = v.expression()
-		case RangeOp:
-			expr = v.expression()
-		case WhereGroup:
-			expr = v.expression()
-		}
+// validate checks that the dialect can qualify the table name.
+func (t Table) validate(dialect string) error {
+	if t.Database == "" {
+		return nil
+	}
+	if t.Schema == "" {
+		return fmt.Errorf(
+			"%w: database %q of %q needs a schema",
+			ErrInvalidTable,
+			t.Database,
+			t.Name,
+		)
+	}
+	if !capabilitiesOf(dialect).CrossDatabase {
+		return fmt.Errorf("%w: database-qualified table on %q", ErrUnsupportedByDialect, dialect)
+	}
+	return nil
+}
 
-		if expr != nil {
-			onConds = append(onConds, expr)
-		}
+// ErrInvalidJoin is returned when a relation cannot be rendered as a JOIN clause.
+var ErrInvalidJoin = errors.New("invalid join")
+
+// Relation represents a JOIN relationship between tables.
+// The On field should contain Condition types (BoolOp, RangeOp, or WhereGroup). Using joins on
+// columns of the same name in both tables, and Keys on joined columns equal to columns of the
+// parent table (the table listing the relation, or "alias.column" for another one); Keys are
+// added to the On conditions. CROSS and NATURAL joins take no conditions. Query joins a
+// subquery aliased as Table.Alias instead of the table itself; Lateral lets it reference the
+// tables joined before it, and a lateral join without conditions is joined ON TRUE.
+type Relation struct {
+	JoinType exp.JoinType      `json:"joinType"          yaml:"joinType"`
+	Lateral  bool              `json:"lateral,omitempty" yaml:"lateral,omitempty"`
+	Query    *SQLBuilder       `json:"query,omitempty"   yaml:"query,omitempty"`
+	On       []any             `json:"on,omitempty"      yaml:"on,omitempty"`
+	Using    []string          `json:"using,omitempty"   yaml:"using,omitempty"`
+	Keys     map[string]string `json:"keys,omitempty"    yaml:"keys,omitempty"`
+	Table    Table             `json:"table"             yaml:"table"`
+}
+
+// join applies this relation as a JOIN clause to the given dataset, joined to the table
+// aliased parentAlias. It recursively applies nested relations (joins on joined tables).
+func (r Relation) join(
+	rc *renderContext,
+	ds *goqu.SelectDataset,
+	parentAlias string,
+) *goqu.SelectDataset {
+	if err := r.validate(rc.dialect); err != nil {
+		rc.fail(err)
+		return ds
 	}
 
 	// Apply the appropriate join type
+	source := r.source(rc)
 	switch r.JoinType {
-	case exp.InnerJoinType:
-		ds = ds.InnerJoin(goqu.T(r.Table.Name).As(r.Table.Alias), goqu.On(onConds...))
-	case exp.LeftJoinType:
-		ds = ds.LeftJoin(goqu.T(r.Table.Name).As(r.Table.Alias), goqu.On(onConds...))
-	case exp.RightJoinType:
-		ds = ds.RightJoin(goqu.T(r.Table.Name).As(r.Table.Alias), goqu.On(onConds...))
+	case exp.LeftJoinType, exp.LeftOuterJoinType:
+		ds = ds.LeftJoin(source, r.condition(rc, parentAlias))
+	case exp.RightJoinType, exp.RightOuterJoinType:
+		ds = ds.RightJoin(source, r.condition(rc, parentAlias))
+	case exp.FullJoinType, exp.FullOuterJoinType:
+		ds = ds.FullOuterJoin(source, r.condition(rc, parentAlias))
+	case exp.CrossJoinType:
+		ds = ds.CrossJoin(source)
+	case exp.NaturalJoinType:
+		ds = ds.NaturalJoin(source)
+	case exp.NaturalLeftJoinType:
+		ds = ds.NaturalLeftJoin(source)
+	case exp.NaturalRightJoinType:
+		ds = ds.NaturalRightJoin(source)
+	case exp.NaturalFullJoinType:
+		ds = ds.NaturalFullJoin(source)
 	default:
-		ds = ds.Join(goqu.T(r.Table.Name).As(r.Table.Alias), goqu.On(onConds...))
+		ds = ds.InnerJoin(source, r.condition(rc, parentAlias))
 	}
 
 	// Recursively apply nested joins
 	for _, child := range r.Table.Relations {
-		ds = child.join(ds)
+		ds = child.join(rc, ds, r.Table.Alias)
 	}
 
 	return ds
 }
 
+// condition builds the join condition: USING for Using columns, otherwise ON the On
+// conditions and Keys. Dialects without USING compare the columns of both tables instead.
+func (r Relation) condition(rc *renderContext, parentAlias string) exp.JoinCondition {
+	if len(r.Using) > 0 {
+		if capabilitiesOf(rc.dialect).JoinUsing {
+			columns := make([]any, len(r.Using))
+			for i, column := range r.Using {
+				columns[i] = column
+			}
+			return goqu.Using(columns...)
+		}
+
+		onConds := make([]exp.Expression, len(r.Using))
+		for i, column := range r.Using {
+			onConds[i] = goqu.C(column).Table(r.Table.Alias).Eq(goqu.C(column).Table(parentAlias))
+		}
+		return goqu.On(onConds...)
+	}
+
+	onConds := make([]exp.Expression, 0, len(r.On)+len(r.Keys))
+	for _, on := range r.On {
+		cond, ok := on.(Condition)
+		if !ok {
+			rc.fail(fmt.Errorf("%w: %T cannot be used as a join condition", ErrInvalidJoin, on))
+			continue
+		}
+		onConds = append(onConds, cond.toExpression(rc))
+	}
+
+	// Keys are sorted so the SQL does not depend on map order
+	columns := make([]string, 0, len(r.Keys))
+	for column := range r.Keys {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		tableAlias, parentColumn := parentAlias, r.Keys[column]
+		if alias, name, qualified := strings.Cut(parentColumn, "."); qualified {
+			tableAlias, parentColumn = alias, name
+		}
+		onConds = append(onConds, goqu.C(column).Table(r.Table.Alias).
+			Eq(goqu.C(parentColumn).Table(tableAlias)))
+	}
+
+	if len(onConds) == 0 {
+		onConds = append(onConds, goqu.L("TRUE"))
+	}
+	return goqu.On(onConds...)
+}
+
+// source returns the joined table, or the aliased subquery when Query is set. Unlike
+// subqueries in conditions, a joined subquery keeps its limit and offset.
+func (r Relation) source(rc *renderContext) exp.Expression {
+	if r.Query == nil {
+		return r.Table.identifier().As(r.Table.Alias)
+	}
+
+	query := *r.Query
+	rc.inherit(&query)
+	subquery := query.applyLimitOffset(query.mainSelect()).As(r.Table.Alias)
+	if r.Lateral {
+		return goqu.Lateral(subquery)
+	}
+	return subquery
+}
+
+// validate checks that the join has the conditions its type needs and that the dialect
+// supports it.
+func (r Relation) validate(dialect string) error {
+	name := joinTypeToString(r.JoinType)
+	conditioned := exp.ConditionedJoinTypes[r.JoinType] || !knownJoinType(r.JoinType)
+	hasConditions := len(r.On) > 0 || len(r.Keys) > 0 || len(r.Using) > 0
+	switch {
+	case len(r.Using) > 0 && (len(r.On) > 0 || len(r.Keys) > 0):
+		return fmt.Errorf(
+			"%w: join of %q cannot combine using with on or keys",
+			ErrInvalidJoin,
+			r.Table.Alias,
+		)
+	case conditioned && !hasConditions && !r.Lateral:
+		return fmt.Errorf(
+			"%w: %s join of %q needs on conditions",
+			ErrInvalidJoin,
+			name,
+			r.Table.Alias,
+		)
+	case !conditioned && hasConditions:
+		return fmt.Errorf(
+			"%w: %s join of %q takes no on conditions",
+			ErrInvalidJoin,
+			name,
+			r.Table.Alias,
+		)
+	case r.Lateral && r.Query == nil:
+		return fmt.Errorf("%w: lateral join of %q needs a query", ErrInvalidJoin, r.Table.Alias)
+	case r.Query == nil:
+		if err := r.Table.validate(dialect); err != nil {
+			return err
+		}
+	}
+
+	caps := capabilitiesOf(dialect)
+	switch {
+	case (r.JoinType == exp.FullJoinType || r.JoinType == exp.FullOuterJoinType ||
+		r.JoinType == exp.NaturalFullJoinType) && !caps.FullJoin:
+		return fmt.Errorf("%w: FULL OUTER join on %q", ErrUnsupportedByDialect, dialect)
+	case r.JoinType >= exp.NaturalJoinType && r.JoinType <= exp.NaturalFullJoinType &&
+		!caps.NaturalJoin:
+		return fmt.Errorf("%w: NATURAL join on %q", ErrUnsupportedByDialect, dialect)
+	case r.Lateral && !caps.LateralJoin:
+		return fmt.Errorf("%w: LATERAL join on %q", ErrUnsupportedByDialect, dialect)
+	}
+	return nil
+}
+
 // ParseJoinType converts a string to a goqu JoinType.
-// Supported values: "left", "right", "inner" (default).
+// Supported values: "inner" (default), "left", "right", "full" / "full outer", "cross",
+// "natural", "natural left", "natural right" and "natural full", in any case.
 func ParseJoinType(s string) exp.JoinType {
-	switch strings.ToLower(strings.TrimSpace(s)) {
-	case "left":
+	switch strings.Join(strings.Fields(strings.ToLower(s)), " ") {
+	case "left", "left outer":
 		return exp.LeftJoinType
-	case "right":
+	case "right", "right outer":
 		return exp.RightJoinType
-	case "inner":
-		return exp.InnerJoinType
+	case "full", "full outer":
+		return exp.FullOuterJoinType
+	case "cross":
+		return exp.CrossJoinType
+	case "natural":
+		return exp.NaturalJoinType
+	case "natural left":
+		return exp.NaturalLeftJoinType
+	case "natural right":
+		return exp.NaturalRightJoinType
+	case "natural full":
+		return exp.NaturalFullJoinType
 	default:
 		return exp.InnerJoinType
 	}
 }
 
+// knownJoinType reports whether jt is one of goqu's join types.
+func knownJoinType(jt exp.JoinType) bool {
+	return jt >= exp.InnerJoinType && jt <= exp.CrossJoinType
+}
+
 // MarshalJSON implements custom JSON marshaling for Relation.
 func (r Relation) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		JoinType string `json:"joinType"`
-		On       []any  `json:"on,omitempty"`
-		Table    Table  `json:"table"`
+		JoinType string            `json:"joinType"`
+		Lateral  bool              `json:"lateral,omitempty"`
+		Query    *SQLBuilder       `json:"query,omitempty"`
+		On       []any             `json:"on,omitempty"`
+		Using    []string          `json:"using,omitempty"`
+		Keys     map[string]string `json:"keys,omitempty"`
+		Table    Table             `json:"table"`
 	}{
 		JoinType: joinTypeToString(r.JoinType),
+		Lateral:  r.Lateral,
+		Query:    r.Query,
 		On:       r.On,
+		Using:    r.Using,
+		Keys:     r.Keys,
 		Table:    r.Table,
 	})
 }
@@ -103,7 +297,11 @@ func (r Relation) MarshalJSON() ([]byte, error) {
 func (r *Relation) UnmarshalJSON(data []byte) error {
 	aux := &struct {
 		JoinType string            `json:"joinType"`
+		Lateral  bool              `json:"lateral,omitempty"`
+		Query    *SQLBuilder       `json:"query,omitempty"`
 		On       []json.RawMessage `json:"on,omitempty"`
+		Using    []string          `json:"using,omitempty"`
+		Keys     map[string]string `json:"keys,omitempty"`
 		Table    Table             `json:"table"`
 	}{}
 
@@ -112,6 +310,10 @@ func (r *Relation) UnmarshalJSON(data []byte) error {
 	}
 
 	r.JoinType = stringToJoinType(aux.JoinType)
+	r.Lateral = aux.Lateral
+	r.Query = aux.Query
+	r.Using = aux.Using
+	r.Keys = aux.Keys
 	r.Table = aux.Table
 
 	// Unmarshal On conditions with type detection
@@ -132,12 +334,20 @@ func (r *Relation) UnmarshalJSON(data []byte) error {
 // MarshalYAML implements custom YAML marshaling for Relation.
 func (r Relation) MarshalYAML() (interface{}, error) {
 	return &struct {
-		JoinType string `yaml:"joinType"`
-		On       []any  `yaml:"on,omitempty"`
-		Table    Table  `yaml:"table"`
+		JoinType string            `yaml:"joinType"`
+		Lateral  bool              `yaml:"lateral,omitempty"`
+		Query    *SQLBuilder       `yaml:"query,omitempty"`
+		On       []any             `yaml:"on,omitempty"`
+		Using    []string          `yaml:"using,omitempty"`
+		Keys     map[string]string `yaml:"keys,omitempty"`
+		Table    Table             `yaml:"table"`
 	}{
 		JoinType: joinTypeToString(r.JoinType),
+		Lateral:  r.Lateral,
+		Query:    r.Query,
 		On:       r.On,
+		Using:    r.Using,
+		Keys:     r.Keys,
 		Table:    r.Table,
 	}, nil
 }
@@ -146,7 +356,11 @@ func (r Relation) MarshalYAML() (interface{}, error) {
 func (r *Relation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	aux := &struct {
 		JoinType string                   `yaml:"joinType"`
+		Lateral  bool                     `yaml:"lateral,omitempty"`
+		Query    *SQLBuilder              `yaml:"query,omitempty"`
 		On       []map[string]interface{} `yaml:"on,omitempty"`
+		Using    []string                 `yaml:"using,omitempty"`
+		Keys     map[string]string        `yaml:"keys,omitempty"`
 		Table    Table                    `yaml:"table"`
 	}{}
 
@@ -155,6 +369,10 @@ func (r *Relation) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	}
 
 	r.JoinType = stringToJoinType(aux.JoinType)
+	r.Lateral = aux.Lateral
+	r.Query = aux.Query
+	r.Using = aux.Using
+	r.Keys = aux.Keys
 	r.Table = aux.Table
 
 	// Unmarshal On conditions with type detection
@@ -182,32 +400,27 @@ func joinTypeToString(jt exp.JoinType) string {
 	switch jt {
 	case exp.InnerJoinType:
 		return "INNER"
-	case exp.LeftJoinType:
+	case exp.LeftJoinType, exp.LeftOuterJoinType:
 		return "LEFT"
-	case exp.RightJoinType:
+	case exp.RightJoinType, exp.RightOuterJoinType:
 		return "RIGHT"
-	case exp.FullOuterJoinType:
+	case exp.FullJoinType, exp.FullOuterJoinType:
 		return "FULL OUTER"
 	case exp.CrossJoinType:
 		return "CROSS"
+	case exp.NaturalJoinType:
+		return "NATURAL"
+	case exp.NaturalLeftJoinType:
+		return "NATURAL LEFT"
+	case exp.NaturalRightJoinType:
+		return "NATURAL RIGHT"
+	case exp.NaturalFullJoinType:
+		return "NATURAL FULL"
 	default:
 		return "INNER"
 	}
 }
 
 func stringToJoinType(s string) exp.JoinType {
-	switch s {
-	case "INNER":
-		return exp.InnerJoinType
-	case "LEFT":
-		return exp.LeftJoinType
-	case "RIGHT":
-		return exp.RightJoinType
-	case "FULL OUTER":
-		return exp.FullOuterJoinType
-	case "CROSS":
-		return exp.CrossJoinType
-	default:
-		return exp.InnerJoinType
-	}
+	return ParseJoinType(s)
 }
@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestArith tests arithmetic and concatenation expressions
+func TestArith(t *testing.T) {
+	price := supersaiyan.F("price", supersaiyan.WithTable("o"))
+	quantity := supersaiyan.F("quantity", supersaiyan.WithTable("o"))
+	discount := supersaiyan.F("discount", supersaiyan.WithTable("o"))
+
+	t.Run("nests with explicit parentheses", func(t *testing.T) {
+		total := supersaiyan.Sub(supersaiyan.Mul(price, quantity), discount)
+
+		sql, args, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.Exp("total", total)).
+			Where(supersaiyan.BoolOp{Op: exp.GtOp, Left: total, Value: 100}).
+			OrderBy(supersaiyan.DescExp(total)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT (("o"."price" * "o"."quantity") - "o"."discount") AS "total" FROM "orders" AS "o" `+
+				`WHERE ((("o"."price" * "o"."quantity") - "o"."discount") > ?) `+
+				`ORDER BY (("o"."price" * "o"."quantity") - "o"."discount") DESC`,
+			sql,
+		)
+		assert.Equal(t, []any{int64(100)}, args)
+	})
+
+	t.Run("renders every operator", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			expr     supersaiyan.Arith
+			expected string
+		}{
+			{"add", supersaiyan.Add(price, 1, 2), `("o"."price" + ? + ?)`},
+			{"div", supersaiyan.Div(price, quantity), `("o"."price" / "o"."quantity")`},
+			{"mod", supersaiyan.Mod(quantity, 2), `("o"."quantity" % ?)`},
+			{
+				"neg",
+				supersaiyan.Neg(supersaiyan.Add(price, discount)),
+				`(-("o"."price" + "o"."discount"))`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sql, _, err := supersaiyan.New("postgres", "orders", "o").
+					WithFields(supersaiyan.Exp("x", tt.expr)).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Contains(t, sql, tt.expected+` AS "x"`)
+			})
+		}
+	})
+
+	t.Run("concatenates per dialect", func(t *testing.T) {
+		fullName := supersaiyan.Concat(
+			supersaiyan.F("first_name", supersaiyan.WithTable("u")),
+			" ",
+			supersaiyan.F("last_name", supersaiyan.WithTable("u")),
+		)
+
+		expected := map[string]string{
+			"postgres":  `("u"."first_name" || ? || "u"."last_name")`,
+			"sqlite3":   `("u"."first_name" || ? || "u"."last_name")`,
+			"mysql":     `CONCAT("u"."first_name", ?, "u"."last_name")`,
+			"sqlserver": `("u"."first_name" + ? + "u"."last_name")`,
+		}
+
+		for dialect, want := range expected {
+			t.Run(dialect, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(dialect, "users", "u").
+					WithFields(supersaiyan.Exp("full_name", fullName)).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Contains(t, sql, want+` AS "full_name"`)
+				assert.Equal(t, []any{" "}, args)
+			})
+		}
+	})
+
+	t.Run("reports malformed expressions", func(t *testing.T) {
+		invalid := []supersaiyan.Arith{
+			supersaiyan.Add(price),
+			supersaiyan.Mul(),
+			{Op: supersaiyan.ModOp, Operands: []any{1, 2, 3}},
+			{Op: supersaiyan.NegOp, Operands: []any{1, 2}},
+			{Op: "^", Operands: []any{1, 2}},
+		}
+
+		for _, expr := range invalid {
+			_, _, err := supersaiyan.New("postgres", "orders", "o").
+				WithFields(supersaiyan.Exp("x", expr)).
+				Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression, expr.Op)
+		}
+	})
+
+	t.Run("round-trips through JSON and YAML", func(t *testing.T) {
+		total := supersaiyan.Sub(supersaiyan.Mul(price, quantity), discount)
+
+		jsonBytes, err := json.Marshal(supersaiyan.Exp("total", total))
+		require.NoError(t, err)
+
+		var field supersaiyan.Field
+		require.NoError(t, json.Unmarshal(jsonBytes, &field))
+		assert.Equal(t, supersaiyan.Exp("total", total), field)
+
+		yamlStr := `
+fieldAlias: label
+exp:
+  arith: "||"
+  operands:
+    - name: code
+      tableAlias: p
+    - "-"
+    - name: name
+      tableAlias: p
+`
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &field))
+		assert.Equal(t, supersaiyan.Exp("label", supersaiyan.Concat(
+			supersaiyan.F("code", supersaiyan.WithTable("p")),
+			"-",
+			supersaiyan.F("name", supersaiyan.WithTable("p")),
+		)), field)
+	})
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestArrayOperators tests the PostgreSQL array operators
+func TestArrayOperators(t *testing.T) {
+	t.Run("renders array operators", func(t *testing.T) {
+		tests := []struct {
+			cond     supersaiyan.BoolOp
+			expected string
+			args     []any
+		}{
+			{
+				supersaiyan.ArrayContains("tags", "p", []string{"sale", "new"}),
+				`("p"."tags" @> ?)`, []any{`{"sale","new"}`},
+			},
+			{
+				supersaiyan.ArrayContainedBy("tags", "p", []string{"sale", "new", "eco"}),
+				`("p"."tags" <@ ?)`, []any{`{"sale","new","eco"}`},
+			},
+			{
+				supersaiyan.ArrayOverlap("sizes", "p", []int{38, 40}),
+				`("p"."sizes" && ?)`, []any{"{38,40}"},
+			},
+			{
+				supersaiyan.ArrayHas("tags", "p", "sale"),
+				`(? = ANY("p"."tags"))`, []any{"sale"},
+			},
+			{
+				supersaiyan.ArrayOverlap(
+					"tags", "p", supersaiyan.F("tags", supersaiyan.WithTable("c")),
+				),
+				`("p"."tags" && "c"."tags")`, nil,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.expected, func(t *testing.T) {
+				sql, args, err := supersaiyan.New("postgres", "products", "p").
+					Where(tt.cond).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "products" AS "p" WHERE `+tt.expected, sql)
+				if tt.args == nil {
+					assert.Empty(t, args)
+				} else {
+					assert.Equal(t, tt.args, args)
+				}
+			})
+		}
+	})
+
+	t.Run("reports other dialects as unsupported", func(t *testing.T) {
+		for _, dialect := range []string{"mysql", "sqlite3", "sqlserver"} {
+			_, _, err := supersaiyan.New(dialect, "products", "p").
+				Where(supersaiyan.ArrayHas("tags", "p", "sale")).
+				Select()
+			require.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect, dialect)
+			assert.Contains(t, err.Error(), "arrayHas")
+		}
+	})
+
+	t.Run("unmarshals array operator names", func(t *testing.T) {
+		jsonData := `{"op": "OR", "conditions": [
+			{"op": "arrayContains", "fieldName": "tags", "tableAlias": "p", "value": ["sale"]},
+			{"op": "arrayOverlap", "fieldName": "tags", "tableAlias": "p", "value": ["new", "eco"]},
+			{"op": "arrayHas", "fieldName": "tags", "tableAlias": "p", "value": "clearance"}
+		]}`
+
+		var group supersaiyan.WhereGroup
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &group))
+		assert.Equal(t, supersaiyan.Or(
+			supersaiyan.ArrayContains("tags", "p", []any{"sale"}),
+			supersaiyan.ArrayOverlap("tags", "p", []any{"new", "eco"}),
+			supersaiyan.ArrayHas("tags", "p", "clearance"),
+		), group)
+
+		_, args, err := supersaiyan.New("postgres", "products", "p").
+			Where(group).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(t, []any{`{"sale"}`, `{"new","eco"}`, "clearance"}, args)
+	})
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueryBinder tests binding of URL query parameters to conditions
+func TestQueryBinder(t *testing.T) {
+	binder := supersaiyan.QueryBinder{
+		Fields: supersaiyan.FieldMap{
+			"status":  supersaiyan.F("status", supersaiyan.WithTable("u")),
+			"age":     supersaiyan.F("age", supersaiyan.WithTable("u")),
+			"score":   supersaiyan.F("score", supersaiyan.WithTable("u")),
+			"active":  supersaiyan.F("is_active", supersaiyan.WithTable("u")),
+			"created": supersaiyan.F("created_at", supersaiyan.WithTable("u")),
+			"country": supersaiyan.F("country_code", supersaiyan.WithTable("a")),
+		},
+		Types: map[string]supersaiyan.ValueType{
+			"age":     supersaiyan.IntValue,
+			"score":   supersaiyan.FloatValue,
+			"active":  supersaiyan.BoolValue,
+			"created": supersaiyan.TimeValue,
+		},
+		Ignore: []string{"sort", "page"},
+	}
+
+	bind := func(t *testing.T, query string) ([]supersaiyan.Condition, error) {
+		values, err := url.ParseQuery(query)
+		require.NoError(t, err)
+		return binder.Bind(values)
+	}
+
+	t.Run("binds equality, operators and lists", func(t *testing.T) {
+		conds, err := bind(t, "status=active&age[gte]=18&country[in]=US,CA&sort=-age")
+		require.NoError(t, err)
+
+		assert.Equal(t, []supersaiyan.Condition{
+			supersaiyan.Gte("age", "u", int64(18)),
+			supersaiyan.In("country_code", "a", []any{"US", "CA"}),
+			supersaiyan.Eq("status", "u", "active"),
+		}, conds)
+	})
+
+	t.Run("binds ranges", func(t *testing.T) {
+		conds, err := bind(t, "age[between]=18,65&score[notBetween]=0.5,1.5")
+		require.NoError(t, err)
+
+		assert.Equal(t, []supersaiyan.Condition{
+			supersaiyan.Between("age", "u", int64(18), int64(65)),
+			supersaiyan.NotBetween("score", "u", 0.5, 1.5),
+		}, conds)
+	})
+
+	t.Run("binds null checks and typed values", func(t *testing.T) {
+		conds, err := bind(t, "status[isNot]=null&active=true&created[lt]=2024-06-01")
+		require.NoError(t, err)
+		require.Len(t, conds, 3)
+
+		assert.Equal(t, supersaiyan.Eq("is_active", "u", true), conds[0])
+		assert.Equal(
+			t,
+			supersaiyan.Lt("created_at", "u", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+			conds[1],
+		)
+		assert.Equal(t, supersaiyan.IsNotNull("status", "u"), conds[2])
+	})
+
+	t.Run("binds every value of a repeated parameter", func(t *testing.T) {
+		conds, err := bind(t, "status[notLike]=test%25&status[notLike]=demo%25&age[gte]=18")
+		require.NoError(t, err)
+
+		notLike := func(pattern string) supersaiyan.BoolOp {
+			return supersaiyan.BoolOp{
+				Op:         exp.NotLikeOp,
+				FieldName:  "status",
+				TableAlias: "u",
+				Value:      pattern,
+			}
+		}
+		assert.Equal(t, []supersaiyan.Condition{
+			supersaiyan.Gte("age", "u", int64(18)),
+			notLike("test%"),
+			notLike("demo%"),
+		}, conds)
+	})
+
+	t.Run("binds expression fields", func(t *testing.T) {
+		lower := supersaiyan.Func{
+			Name: "LOWER",
+			Args: []any{supersaiyan.F("email", supersaiyan.WithTable("u"))},
+		}
+		binder := supersaiyan.QueryBinder{
+			Fields: supersaiyan.FieldMap{"email": supersaiyan.Exp("email", lower)},
+		}
+
+		conds, err := binder.Bind(url.Values{"email": {"jo@example.com"}})
+		require.NoError(t, err)
+		assert.Equal(t, []supersaiyan.Condition{
+			supersaiyan.BoolOp{Op: exp.EqOp, Left: lower, Value: "jo@example.com"},
+		}, conds)
+	})
+
+	t.Run("rejects unknown parameters", func(t *testing.T) {
+		_, err := bind(t, "password=secret")
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("rejects invalid operators and values", func(t *testing.T) {
+		invalid := []string{
+			"age[gte=18",
+			"age[]=18",
+			"age[approx]=18",
+			"age[regexpLike]=1.*",
+			"status[contains]=x",
+			"status[jsonContains]={}",
+			"age=eighteen",
+			"age[between]=18",
+			"status[is]=maybe",
+			"created=yesterday",
+		}
+		for _, query := range invalid {
+			_, err := bind(t, query)
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidParameter, query)
+		}
+	})
+
+	t.Run("bound conditions render in queries", func(t *testing.T) {
+		conds, err := bind(t, "status=active&age[gte]=18")
+		require.NoError(t, err)
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").Where(conds...).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE (("u"."age" >= ?) AND ("u"."status" = ?))`)
+		assert.Equal(t, []any{int64(18), "active"}, args)
+	})
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCast tests CAST expressions with portable type names
+func TestCast(t *testing.T) {
+	createdAt := supersaiyan.F("created_at", supersaiyan.WithTable("o"))
+
+	t.Run("maps portable types per dialect", func(t *testing.T) {
+		value := supersaiyan.F("v", supersaiyan.WithTable("o"))
+		tests := []struct {
+			typeName string
+			dialect  string
+			expected string
+		}{
+			{"int", "postgres", `CAST("o"."v" AS INTEGER)`},
+			{"int", "mysql", `CAST("o"."v" AS SIGNED)`},
+			{"int", "sqlserver", `CAST("o"."v" AS INT)`},
+			{"bigint", "sqlite3", `CAST("o"."v" AS INTEGER)`},
+			{"decimal(10, 2)", "postgres", `CAST("o"."v" AS NUMERIC(10,2))`},
+			{"DECIMAL(10,2)", "mysql", `CAST("o"."v" AS DECIMAL(10,2))`},
+			{"decimal(8)", "sqlserver", `CAST("o"."v" AS DECIMAL(8))`},
+			{"decimal", "sqlite3", `CAST("o"."v" AS NUMERIC)`},
+			{"text", "mysql", `CAST("o"."v" AS CHAR)`},
+			{"text", "sqlserver", `CAST("o"."v" AS NVARCHAR(MAX))`},
+			{"date", "postgres", `CAST("o"."v" AS DATE)`},
+			{"date", "sqlite3", `DATE("o"."v")`},
+			{"timestamp", "mysql", `CAST("o"."v" AS DATETIME)`},
+			{"timestamp", "sqlserver", `CAST("o"."v" AS DATETIME2)`},
+			{"timestamp", "sqlite3", `DATETIME("o"."v")`},
+			{"boolean", "postgres", `CAST("o"."v" AS BOOLEAN)`},
+			{"boolean", "sqlserver", `CAST("o"."v" AS BIT)`},
+			{"json", "postgres", `CAST("o"."v" AS JSONB)`},
+			{"json", "mysql", `CAST("o"."v" AS JSON)`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.typeName+"/"+tt.dialect, func(t *testing.T) {
+				sql, _, err := supersaiyan.New(tt.dialect, "orders", "o").
+					WithFields(supersaiyan.Exp("x", supersaiyan.CastAs(value, tt.typeName))).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Contains(t, sql, tt.expected+` AS "x"`)
+			})
+		}
+	})
+
+	t.Run("works in conditions, sorts and grouping", func(t *testing.T) {
+		day := supersaiyan.CastAs(createdAt, "date")
+
+		sql, args, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(
+				supersaiyan.Exp("day", day),
+				supersaiyan.Exp("orders", supersaiyan.Count()),
+			).
+			Where(supersaiyan.RangeOp{
+				Op:    exp.BetweenOp,
+				Left:  day,
+				Start: "2024-01-01",
+				End:   "2024-01-31",
+			}).
+			GroupByFields(supersaiyan.Field{Exp: day}).
+			OrderBy(supersaiyan.AscExp(day)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT CAST("o"."created_at" AS DATE) AS "day", COUNT(*) AS "orders" FROM "orders" AS "o" `+
+				`WHERE (CAST("o"."created_at" AS DATE) BETWEEN ? AND ?) `+
+				`GROUP BY CAST("o"."created_at" AS DATE) ORDER BY CAST("o"."created_at" AS DATE) ASC`,
+			sql,
+		)
+		assert.Equal(t, []any{"2024-01-01", "2024-01-31"}, args)
+	})
+
+	t.Run("rejects unknown types", func(t *testing.T) {
+		for _, typeName := range []string{"varchar2", "decimal(a,b)", "int; DROP TABLE x"} {
+			_, _, err := supersaiyan.New("postgres", "orders", "o").
+				WithFields(supersaiyan.Exp("x", supersaiyan.CastAs(createdAt, typeName))).
+				Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression, typeName)
+		}
+	})
+
+	t.Run("unmarshals cast documents", func(t *testing.T) {
+		jsonData := `{"fieldAlias": "day", "exp": {"cast": {"name": "created_at", "tableAlias": "o"}, "as": "date"}}`
+
+		var field supersaiyan.Field
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &field))
+		assert.Equal(t, supersaiyan.Exp("day", supersaiyan.CastAs(createdAt, "date")), field)
+	})
+}
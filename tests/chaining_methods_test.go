@@ -205,6 +205,72 @@ func TestWhere(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, sql, "IS NULL")
 	})
+
+	t.Run("adds NOT condition group", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			Where(
+				supersaiyan.Eq("active", "u", true),
+				supersaiyan.Not(supersaiyan.Or(
+					supersaiyan.Eq("role", "u", "guest"),
+					supersaiyan.IsNull("email", "u"),
+				)),
+			).
+			Limit(0)
+
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`WHERE (("u"."active" = ?) AND (NOT (("u"."role" = ?) OR ("u"."email" IS NULL))))`,
+		)
+		assert.Equal(t, []any{true, "guest"}, args)
+	})
+
+	t.Run("renders literals and subqueries inside groups", func(t *testing.T) {
+		orders := supersaiyan.New("postgres", "orders", "o").
+			Where(supersaiyan.Eq("user_id", "o", supersaiyan.F("id", supersaiyan.WithTable("u"))))
+
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.Not(supersaiyan.L("EXISTS ?", *orders))).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" WHERE (NOT EXISTS (SELECT * FROM "orders" AS "o" `+
+				`WHERE ("o"."user_id" = "u"."id")))`,
+			sql,
+		)
+
+		verified := supersaiyan.New("postgres", "profiles", "p").
+			WithFields(supersaiyan.F("verified", supersaiyan.WithTable("p"))).
+			Where(supersaiyan.Eq("user_id", "p", supersaiyan.F("id", supersaiyan.WithTable("u"))))
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.Or(supersaiyan.Eq("role", "u", "admin"), *verified)).
+			Delete()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`DELETE FROM "users" WHERE (("u"."role" = ?) OR (SELECT "p"."verified" `+
+				`FROM "profiles" AS "p" WHERE ("p"."user_id" = "u"."id")))`,
+			sql,
+		)
+		assert.Equal(t, []any{"admin"}, args)
+	})
+
+	t.Run("rejects values that cannot be conditions", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.And(supersaiyan.Eq("id", "u", 1), "active")).
+			Delete()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidCondition)
+
+		_, _, err = supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.Not(nil)).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidCondition)
+	})
 }
 
 // TestOrderBy tests the OrderBy chaining method
@@ -247,6 +313,95 @@ func TestOrderBy(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, sql, "ORDER BY")
 	})
+
+	t.Run("sorts by selected field alias", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.Exp("order_count", supersaiyan.L("COUNT(*)"))).
+			OrderBy(supersaiyan.DescAlias("order_count")).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `ORDER BY "order_count" DESC`)
+	})
+
+	t.Run("sorts by expression", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			OrderBy(
+				supersaiyan.AscExp(supersaiyan.Coal(
+					nil,
+					supersaiyan.F("nickname", supersaiyan.WithTable("u")),
+					supersaiyan.F("username", supersaiyan.WithTable("u")),
+				)),
+				supersaiyan.AscExp(supersaiyan.L("RANDOM()")),
+			).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`ORDER BY COALESCE("u"."nickname", "u"."username") ASC, RANDOM() ASC`,
+		)
+	})
+
+	t.Run("renders NULLS FIRST and NULLS LAST natively", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			OrderBy(
+				supersaiyan.Asc("last_login", "u").NullsFirst(),
+				supersaiyan.Desc("score", "u").NullsLast(),
+			).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`ORDER BY "u"."last_login" ASC NULLS FIRST, "u"."score" DESC NULLS LAST`,
+		)
+	})
+
+	t.Run("emulates NULLS placement on mysql and sqlserver", func(t *testing.T) {
+		for _, dialect := range []string{"mysql", "sqlserver"} {
+			qb := supersaiyan.New(dialect, "users", "u").
+				OrderBy(supersaiyan.Desc("score", "u").NullsLast()).
+				Limit(0)
+
+			sql, args, err := qb.Select()
+			require.NoError(t, err)
+			assert.NotContains(t, sql, "NULLS")
+			assert.Contains(
+				t,
+				sql,
+				`ORDER BY CASE  WHEN ("u"."score" IS NULL) THEN ? ELSE ? END ASC, "u"."score" DESC`,
+			)
+			assert.Equal(t, []any{int64(1), int64(0)}, args)
+		}
+	})
+
+	t.Run("emulates NULLS placement on an alias with the selected expression", func(t *testing.T) {
+		count := supersaiyan.L("COUNT(?)", supersaiyan.F("id", supersaiyan.WithTable("u")))
+		qb := supersaiyan.New("sqlserver", "users", "u").
+			WithFields(supersaiyan.Exp("cnt", count)).
+			OrderBy(supersaiyan.DescAlias("cnt").NullsLast()).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`ORDER BY CASE  WHEN (COUNT("u"."id") IS NULL) THEN ? ELSE ? END ASC, "cnt" DESC`,
+		)
+
+		_, _, err = supersaiyan.New("sqlserver", "users", "u").
+			OrderBy(supersaiyan.DescAlias("cnt").NullsLast()).
+			Limit(0).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+	})
 }
 
 // TestGroupByFields tests the GroupByFields chaining method
@@ -1245,3 +1400,101 @@ func TestField_AliasedExpression(t *testing.T) {
 		assert.Contains(t, sql, "total")
 	})
 }
+
+// TestBoolOp_LeftExpression tests comparisons with an expression as the left operand
+func TestBoolOp_LeftExpression(t *testing.T) {
+	t.Run("literal function call", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.BoolOp{
+				Op: exp.EqOp,
+				Left: supersaiyan.L(
+					"LOWER(?)",
+					supersaiyan.F("email", supersaiyan.WithTable("u")),
+				),
+				Value: "john@example.com",
+			}).
+			Limit(0)
+
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE (LOWER("u"."email") = ?)`)
+		assert.Equal(t, []any{"john@example.com"}, args)
+	})
+
+	t.Run("arithmetic literal in a range", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "orders", "o").
+			Where(supersaiyan.RangeOp{
+				Op:    exp.BetweenOp,
+				Left:  supersaiyan.L(`"o"."price" * "o"."qty"`),
+				Start: 10,
+				End:   100,
+			}).
+			Limit(0)
+
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE ("o"."price" * "o"."qty" BETWEEN ? AND ?)`)
+		assert.Equal(t, []any{int64(10), int64(100)}, args)
+	})
+
+	t.Run("field ignores its alias", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.BoolOp{
+				Op: exp.GtOp,
+				Left: supersaiyan.F(
+					"age",
+					supersaiyan.WithTable("u"),
+					supersaiyan.WithAlias("years"),
+				),
+				Value: 18,
+			}).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE ("u"."age" > ?)`)
+	})
+
+	t.Run("coalesce and case", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			Where(
+				supersaiyan.BoolOp{
+					Op: exp.InOp,
+					Left: supersaiyan.Coal(
+						"none",
+						supersaiyan.F("role", supersaiyan.WithTable("u")),
+					),
+					Value: []string{"admin", "none"},
+				},
+				supersaiyan.BoolOp{
+					Op: exp.EqOp,
+					Left: supersaiyan.C(
+						"adult",
+						supersaiyan.WT(supersaiyan.Lt("age", "u", 18), "minor"),
+					),
+					Value: "adult",
+				},
+			).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `(COALESCE("u"."role", ?) IN ((?, ?)))`)
+		assert.Contains(t, sql, `(CASE  WHEN ("u"."age" < ?) THEN ? ELSE ? END = ?)`)
+	})
+
+	t.Run("expression field wins over fieldName", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.BoolOp{
+				Op:        exp.LikeOp,
+				FieldName: "email",
+				Left:      supersaiyan.Exp("total", supersaiyan.L("UPPER(name)")),
+				Value:     "J%",
+			}).
+			Limit(0)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE (UPPER(name) LIKE ?)`)
+	})
+}
@@ -0,0 +1,272 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// fixedNow is a Wednesday used as the clock for relative time tests.
+var fixedNow = time.Date(2024, time.March, 13, 15, 42, 7, 0, time.UTC)
+
+// TestDateFunc tests portable date/time expressions
+func TestDateFunc(t *testing.T) {
+	createdAt := supersaiyan.F("created_at", supersaiyan.WithTable("o"))
+
+	t.Run("renders per dialect", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			dialect  string
+			date     supersaiyan.DateFunc
+			expected string
+			args     []any
+		}{
+			{"now", "postgres", supersaiyan.Now(), `CURRENT_TIMESTAMP`, nil},
+			{
+				"trunc", "postgres", supersaiyan.DateTrunc("month", createdAt),
+				`DATE_TRUNC('month', "o"."created_at")`, nil,
+			},
+			{
+				"trunc", "mysql", supersaiyan.DateTrunc("month", createdAt),
+				`CAST(DATE_FORMAT("o"."created_at", ?) AS DATETIME)`, []any{"%Y-%m-01"},
+			},
+			{
+				"trunc week", "mysql", supersaiyan.DateTrunc("week", createdAt),
+				`CAST(DATE_SUB(DATE("o"."created_at"), ` +
+					`INTERVAL WEEKDAY("o"."created_at") DAY) AS DATETIME)`,
+				nil,
+			},
+			{
+				"trunc", "sqlite3", supersaiyan.DateTrunc("day", createdAt),
+				`STRFTIME(?, "o"."created_at")`, []any{"%Y-%m-%d 00:00:00"},
+			},
+			{
+				"trunc", "sqlserver", supersaiyan.DateTrunc("month", createdAt),
+				`DATEADD(month, DATEDIFF(month, 0, "o"."created_at"), 0)`, nil,
+			},
+			{
+				"add", "postgres", supersaiyan.DateAdd(createdAt, 30, "day"),
+				`("o"."created_at" + INTERVAL '30 day')`, nil,
+			},
+			{
+				"sub", "mysql", supersaiyan.DateSub(createdAt, 2, "hour"),
+				`DATE_ADD("o"."created_at", INTERVAL -2 HOUR)`, nil,
+			},
+			{
+				"sub week", "sqlite3", supersaiyan.DateSub(createdAt, 1, "week"),
+				`DATETIME("o"."created_at", '-7 days')`, nil,
+			},
+			{
+				"add", "sqlserver", supersaiyan.DateAdd(createdAt, 3, "MONTH"),
+				`DATEADD(month, 3, "o"."created_at")`, nil,
+			},
+			{
+				"extract", "postgres", supersaiyan.Extract("year", createdAt),
+				`EXTRACT(YEAR FROM "o"."created_at")`, nil,
+			},
+			{
+				"extract", "sqlite3", supersaiyan.Extract("month", createdAt),
+				`CAST(STRFTIME(?, "o"."created_at") AS INTEGER)`, []any{"%m"},
+			},
+			{
+				"extract", "sqlserver", supersaiyan.Extract("week", createdAt),
+				`DATEPART(iso_week, "o"."created_at")`, nil,
+			},
+			{
+				"extract", "postgres", supersaiyan.Extract("week", createdAt),
+				`EXTRACT(WEEK FROM "o"."created_at")`, nil,
+			},
+			{
+				"extract", "mysql", supersaiyan.Extract("week", createdAt),
+				`WEEK("o"."created_at", 3)`, nil,
+			},
+			{
+				"extract", "sqlite3", supersaiyan.Extract("week", createdAt),
+				`((CAST(STRFTIME('%j', DATE("o"."created_at", '-3 days', 'weekday 4')) ` +
+					`AS INTEGER) - 1) / 7 + 1)`, nil,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name+"/"+tt.dialect, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "orders", "o").
+					WithFields(supersaiyan.Exp("d", tt.date)).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT `+tt.expected+` AS "d" FROM "orders" AS "o"`, sql)
+				if tt.args == nil {
+					assert.Empty(t, args)
+				} else {
+					assert.Equal(t, tt.args, args)
+				}
+			})
+		}
+	})
+
+	t.Run("works as the left operand and in grouping", func(t *testing.T) {
+		month := supersaiyan.DateTrunc("month", createdAt)
+		sql, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(
+				supersaiyan.Exp("month", month),
+				supersaiyan.Exp("orders", supersaiyan.Count()),
+			).
+			Where(supersaiyan.BoolOp{
+				Op:    exp.GteOp,
+				Left:  createdAt,
+				Value: supersaiyan.DateSub(supersaiyan.Now(), 30, "day"),
+			}).
+			GroupByFields(supersaiyan.Field{Exp: month}).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT DATE_TRUNC('month', "o"."created_at") AS "month", COUNT(*) AS "orders" `+
+				`FROM "orders" AS "o" `+
+				`WHERE ("o"."created_at" >= (CURRENT_TIMESTAMP + INTERVAL '-30 day')) `+
+				`GROUP BY DATE_TRUNC('month', "o"."created_at")`,
+			sql,
+		)
+	})
+
+	t.Run("rejects unknown units and operations", func(t *testing.T) {
+		for _, date := range []supersaiyan.DateFunc{
+			supersaiyan.DateTrunc("fortnight", createdAt),
+			supersaiyan.DateAdd(createdAt, 1, "day'); DROP TABLE x; --"),
+			{Op: "shift", Unit: "day", Exp: createdAt},
+		} {
+			_, _, err := supersaiyan.New("postgres", "orders", "o").
+				WithFields(supersaiyan.Exp("d", date)).
+				Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression)
+		}
+
+		_, _, err := supersaiyan.New("sqlserver", "orders", "o").
+			WithFields(supersaiyan.Exp("d", supersaiyan.DateTrunc("second", createdAt))).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+	})
+
+	t.Run("unmarshals date documents", func(t *testing.T) {
+		jsonData := `{"fieldAlias": "month", "exp": {"date": "trunc", "unit": "month", "exp": {"name": "created_at", "tableAlias": "o"}}}`
+
+		var field supersaiyan.Field
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &field))
+		assert.Equal(t, supersaiyan.Exp("month", supersaiyan.DateTrunc("month", createdAt)), field)
+	})
+}
+
+// TestRelativeTime tests relative time values resolved against the builder's clock
+func TestRelativeTime(t *testing.T) {
+	t.Run("resolves offsets and starts of units", func(t *testing.T) {
+		tests := []struct {
+			value    supersaiyan.RelativeTime
+			expected time.Time
+		}{
+			{"now", fixedNow},
+			{"now-30d", time.Date(2024, time.February, 12, 15, 42, 7, 0, time.UTC)},
+			{"now-1h+15m", time.Date(2024, time.March, 13, 14, 57, 7, 0, time.UTC)},
+			{"now+2w", time.Date(2024, time.March, 27, 15, 42, 7, 0, time.UTC)},
+			{"now-1M", time.Date(2024, time.February, 13, 15, 42, 7, 0, time.UTC)},
+			{"now-1y-10s", time.Date(2023, time.March, 13, 15, 41, 57, 0, time.UTC)},
+			{"startOf:year", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			{"startOf:month", time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)},
+			{"startOf:month-1M", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+			{"startOf:week", time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)},
+			{"startOf:day-1d", time.Date(2024, time.March, 12, 0, 0, 0, 0, time.UTC)},
+			{"startOf:hour", time.Date(2024, time.March, 13, 15, 0, 0, 0, time.UTC)},
+		}
+
+		for _, tt := range tests {
+			t.Run(string(tt.value), func(t *testing.T) {
+				resolved, err := tt.value.Resolve(fixedNow)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, resolved)
+			})
+		}
+	})
+
+	t.Run("starts weeks on Monday", func(t *testing.T) {
+		sunday := time.Date(2024, time.March, 17, 9, 0, 0, 0, time.UTC)
+		resolved, err := supersaiyan.RelativeTime("startOf:week").Resolve(sunday)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC), resolved)
+	})
+
+	t.Run("rejects unknown units", func(t *testing.T) {
+		invalid := []supersaiyan.RelativeTime{"startOf:fortnight", "now-3x", "yesterday"}
+		for _, value := range invalid {
+			_, err := value.Resolve(fixedNow)
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression, string(value))
+		}
+	})
+
+	t.Run("binds resolved times in queries", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "orders", "o").
+			WithClock(func() time.Time { return fixedNow }).
+			Where(supersaiyan.RangeOp{
+				Op:         exp.BetweenOp,
+				FieldName:  "created_at",
+				TableAlias: "o",
+				Start:      supersaiyan.RelativeTime("startOf:month-1M"),
+				End:        supersaiyan.RelativeTime("startOf:month"),
+			}).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "orders" AS "o" WHERE ("o"."created_at" BETWEEN ? AND ?)`,
+			sql,
+		)
+		assert.Equal(t, []any{
+			time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		}, args)
+	})
+
+	t.Run("parses relative values in YAML reports", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: orders
+  alias: o
+wheres:
+  - op: gte
+    fieldName: created_at
+    tableAlias: o
+    value: {relative: now-30d}
+  - op: eq
+    fieldName: status
+    tableAlias: o
+    value: now
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		assert.Equal(
+			t,
+			supersaiyan.RelativeTime("now-30d"),
+			qb.Wheres[0].(supersaiyan.BoolOp).Value,
+		)
+		assert.Equal(t, "now", qb.Wheres[1].(supersaiyan.BoolOp).Value)
+
+		_, args, err := qb.WithClock(func() time.Time { return fixedNow }).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Equal(t, []any{fixedNow.AddDate(0, 0, -30), "now"}, args)
+
+		jsonBytes, err := json.Marshal(qb.Wheres[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(jsonBytes), `"value":{"relative":"now-30d"}`)
+		var cond supersaiyan.BoolOp
+		require.NoError(t, json.Unmarshal(jsonBytes, &cond))
+		assert.Equal(t, supersaiyan.RelativeTime("now-30d"), cond.Value)
+	})
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDialectCapabilities tests how operators are rewritten for dialects lacking them
+func TestDialectCapabilities(t *testing.T) {
+	matching := func(op exp.BooleanOperation, value string) supersaiyan.BoolOp {
+		return supersaiyan.BoolOp{Op: op, FieldName: "name", TableAlias: "u", Value: value}
+	}
+
+	t.Run("rewrites case-insensitive and regexp matching", func(t *testing.T) {
+		tests := []struct {
+			dialect  string
+			cond     supersaiyan.BoolOp
+			expected string
+		}{
+			{"postgres", matching(exp.ILikeOp, "jo%"), `("u"."name" ILIKE ?)`},
+			{"mysql", matching(exp.LikeOp, "jo%"), `("u"."name" LIKE BINARY ?)`},
+			{"mysql", matching(exp.NotLikeOp, "jo%"), `("u"."name" NOT LIKE BINARY ?)`},
+			{"mysql", matching(exp.ILikeOp, "jo%"), `(LOWER("u"."name") LIKE LOWER(?))`},
+			{
+				"sqlserver", matching(exp.NotILikeOp, "jo%"),
+				`(LOWER("u"."name") NOT LIKE LOWER(?) ESCAPE '\')`,
+			},
+			{"postgres", matching(exp.RegexpLikeOp, "^jo"), `("u"."name" ~ ?)`},
+			{"postgres", matching(exp.RegexpNotILikeOp, "^jo"), `("u"."name" !~* ?)`},
+			{"mysql", matching(exp.RegexpLikeOp, "^jo"), `REGEXP_LIKE("u"."name", ?, 'c')`},
+			{"mysql", matching(exp.RegexpILikeOp, "^jo"), `REGEXP_LIKE("u"."name", ?, 'i')`},
+			{
+				"mysql", matching(exp.RegexpNotILikeOp, "^jo"),
+				`(NOT REGEXP_LIKE("u"."name", ?, 'i'))`,
+			},
+			{"sqlite3", matching(exp.RegexpLikeOp, "^jo"), `("u"."name" REGEXP ?)`},
+			{"sqlite3", matching(exp.RegexpNotLikeOp, "^jo"), `("u"."name" NOT REGEXP ?)`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.dialect+"/"+tt.expected, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "users", "u").
+					Where(tt.cond).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE `+tt.expected, sql)
+				assert.Equal(t, []any{tt.cond.Value}, args)
+			})
+		}
+	})
+
+	t.Run("reports genuinely unsupported operators", func(t *testing.T) {
+		tests := []struct {
+			dialect string
+			cond    supersaiyan.BoolOp
+		}{
+			{"sqlite3", matching(exp.RegexpILikeOp, "^jo")},
+			{"sqlite3", matching(exp.RegexpNotILikeOp, "^jo")},
+			{"sqlserver", matching(exp.RegexpLikeOp, "^jo")},
+			{"mysql", supersaiyan.ArrayHas("tags", "u", "admin")},
+		}
+
+		for _, tt := range tests {
+			_, _, err := supersaiyan.New(tt.dialect, "users", "u").Where(tt.cond).Select()
+			require.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect, tt.dialect)
+			assert.Contains(t, err.Error(), tt.dialect)
+		}
+	})
+}
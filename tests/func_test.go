@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFunc tests typed function call expressions
+func TestFunc(t *testing.T) {
+	amount := supersaiyan.F("amount", supersaiyan.WithTable("o"))
+
+	t.Run("renders aggregates", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(
+				supersaiyan.Exp("orders", supersaiyan.Count()),
+				supersaiyan.Exp(
+					"customers",
+					supersaiyan.CountDistinct(supersaiyan.F("user_id", supersaiyan.WithTable("o"))),
+				),
+				supersaiyan.Exp("total", supersaiyan.Sum(amount)),
+				supersaiyan.Exp("average", supersaiyan.Avg(amount)),
+				supersaiyan.Exp("smallest", supersaiyan.Min(amount)),
+				supersaiyan.Exp("largest", supersaiyan.Max(amount)),
+			).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT COUNT(*) AS "orders", COUNT(DISTINCT "o"."user_id") AS "customers", `+
+				`SUM("o"."amount") AS "total", AVG("o"."amount") AS "average", `+
+				`MIN("o"."amount") AS "smallest", MAX("o"."amount") AS "largest" FROM "orders" AS "o"`,
+			sql,
+		)
+	})
+
+	t.Run("renders FILTER natively", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.Exp("paid", supersaiyan.Sum(amount).FilterBy(
+				supersaiyan.Eq("status", "o", "paid"),
+				supersaiyan.IsNull("refunded_at", "o"),
+			))).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`SUM("o"."amount") FILTER (WHERE (("o"."status" = ?) AND ("o"."refunded_at" IS NULL))) AS "paid"`,
+		)
+		assert.Equal(t, []any{"paid"}, args)
+	})
+
+	t.Run("emulates FILTER with CASE on mysql", func(t *testing.T) {
+		paid := supersaiyan.Eq("status", "o", "paid")
+		sql, _, err := supersaiyan.New("mysql", "orders", "o").
+			WithFields(
+				supersaiyan.Exp("paid", supersaiyan.Count().FilterBy(paid)),
+				supersaiyan.Exp("paid_total", supersaiyan.Sum(amount).FilterBy(paid)),
+			).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `COUNT(CASE  WHEN ("o"."status" = ?) THEN ? END) AS "paid"`)
+		assert.Contains(
+			t,
+			sql,
+			`SUM(CASE  WHEN ("o"."status" = ?) THEN "o"."amount" END) AS "paid_total"`,
+		)
+	})
+
+	t.Run("works in WHERE and ORDER BY", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.BoolOp{
+				Op:    supersaiyan.ParseBoolOperation("="),
+				Left:  supersaiyan.Fn("lower", supersaiyan.F("email")),
+				Value: "a@b.c",
+			}).
+			OrderBy(supersaiyan.DescExp(
+				supersaiyan.Fn("length", supersaiyan.F("name", supersaiyan.WithTable("u"))),
+			)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`WHERE (LOWER("email") = ?) ORDER BY LENGTH("u"."name") DESC`,
+		)
+		assert.Equal(t, []any{"a@b.c"}, args)
+	})
+
+	t.Run("rejects functions not registered for the dialect", func(t *testing.T) {
+		tests := []struct {
+			dialect string
+			fn      supersaiyan.Func
+		}{
+			{"postgres", supersaiyan.Fn("pg_sleep", 10)},
+			{"sqlserver", supersaiyan.Fn("length", amount)},
+			{"postgres", supersaiyan.Fn("count(*); DROP TABLE x; --")},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.fn.Name, func(t *testing.T) {
+				_, _, err := supersaiyan.New(tt.dialect, "orders", "o").
+					WithFields(supersaiyan.Exp("x", tt.fn)).
+					Select()
+				assert.ErrorIs(t, err, supersaiyan.ErrFunctionNotAllowed)
+			})
+		}
+	})
+
+	t.Run("rejects DISTINCT without arguments", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.Exp("n", supersaiyan.Func{Name: "COUNT", Distinct: true})).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression)
+	})
+
+	t.Run("calls functions without arguments with empty parentheses", func(t *testing.T) {
+		for dialect, name := range map[string]string{"postgres": "NOW", "sqlserver": "GETDATE"} {
+			sql, args, err := supersaiyan.New(dialect, "orders", "o").
+				WithFields(supersaiyan.Exp("at", supersaiyan.Fn(name))).
+				Limit(0).
+				Select()
+			require.NoError(t, err)
+			assert.Equal(t, `SELECT `+name+`() AS "at" FROM "orders" AS "o"`, sql, dialect)
+			assert.Empty(t, args)
+		}
+	})
+
+	t.Run("allows registered functions", func(t *testing.T) {
+		supersaiyan.RegisterFunctions("postgres", "date_trunc")
+		createdAt := supersaiyan.F("created_at", supersaiyan.WithTable("o"))
+
+		sql, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.Exp(
+				"day",
+				supersaiyan.Fn("date_trunc", "day", createdAt),
+			)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `DATE_TRUNC(?, "o"."created_at") AS "day"`)
+	})
+
+	t.Run("unmarshals func documents", func(t *testing.T) {
+		jsonData := `{
+			"dialect": "postgres",
+			"table": {"name": "orders", "alias": "o"},
+			"fields": [
+				{"fieldAlias": "paid", "exp": {
+					"func": "sum",
+					"args": [{"name": "amount", "tableAlias": "o"}],
+					"filter": {"op": "eq", "fieldName": "status", "tableAlias": "o", "value": "paid"}
+				}},
+				{"fieldAlias": "customers", "exp": {"func": "count", "distinct": true, "args": [{"name": "user_id"}]}}
+			]
+		}`
+
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &qb))
+
+		fn, ok := qb.Fields[0].Exp.(supersaiyan.Func)
+		require.True(t, ok)
+		assert.Equal(t, "sum", fn.Name)
+		assert.Equal(t, supersaiyan.Eq("status", "o", "paid"), fn.Filter)
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `SUM("o"."amount") FILTER (WHERE ("o"."status" = ?)) AS "paid"`)
+		assert.Contains(t, sql, `COUNT(DISTINCT "user_id") AS "customers"`)
+
+		jsonBytes, err := json.Marshal(supersaiyan.CountDistinct(supersaiyan.F("id")))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"func":"COUNT","args":[{"name":"id"}],"distinct":true}`, string(jsonBytes))
+	})
+}
@@ -204,6 +204,13 @@ func TestWhereGroupHelpers(t *testing.T) {
 		assert.Equal(t, exp.OrType, group.Op)
 		assert.Len(t, group.Conditions, 3)
 	})
+
+	t.Run("Not creates NOT group", func(t *testing.T) {
+		group := supersaiyan.Not(supersaiyan.Eq("status", "u", "banned"))
+
+		assert.Equal(t, supersaiyan.NotType, group.Op)
+		assert.Len(t, group.Conditions, 1)
+	})
 }
 
 // TestSortHelpers tests sort helper functions
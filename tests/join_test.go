@@ -0,0 +1,314 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestJoinTypes tests FULL OUTER, CROSS, NATURAL and LATERAL joins
+func TestJoinTypes(t *testing.T) {
+	userID := supersaiyan.F("id", supersaiyan.WithTable("u"))
+
+	t.Run("renders every join type", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			qb       *supersaiyan.SQLBuilder
+			expected string
+		}{
+			{
+				"full outer",
+				supersaiyan.New("postgres", "users", "u").
+					FullJoin("accounts", "a", supersaiyan.Eq("user_id", "a", userID)),
+				`FULL OUTER JOIN "accounts" AS "a" ON ("a"."user_id" = "u"."id")`,
+			},
+			{
+				"cross",
+				supersaiyan.New("sqlserver", "users", "u").CrossJoin("sizes", "s"),
+				`CROSS JOIN "sizes" AS "s"`,
+			},
+			{
+				"natural",
+				supersaiyan.New("sqlite3", "users", "u").NaturalJoin("profiles", "p"),
+				`NATURAL JOIN "profiles" AS "p"`,
+			},
+			{
+				"natural left",
+				supersaiyan.New("postgres", "users", "u").Join(
+					exp.NaturalLeftJoinType,
+					supersaiyan.Table{Name: "profiles", Alias: "p"},
+				),
+				`NATURAL LEFT JOIN "profiles" AS "p"`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sql, args, err := tt.qb.Limit(0).Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "users" AS "u" `+tt.expected, sql)
+				assert.Empty(t, args)
+			})
+		}
+	})
+
+	t.Run("joins lateral subqueries", func(t *testing.T) {
+		latest := supersaiyan.New("postgres", "orders", "o").
+			Where(supersaiyan.Eq("user_id", "o", userID)).
+			OrderBy(supersaiyan.Desc("created_at", "o")).
+			Limit(3)
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			LateralJoin(exp.LeftJoinType, latest, "recent").
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" LEFT JOIN LATERAL (SELECT * FROM "orders" AS "o" `+
+				`WHERE ("o"."user_id" = "u"."id") ORDER BY "o"."created_at" DESC LIMIT ?) `+
+				`AS "recent" ON TRUE`,
+			sql,
+		)
+		assert.Equal(t, []any{int64(3)}, args)
+
+		sql, _, err = supersaiyan.New("postgres", "users", "u").
+			LateralJoin(exp.CrossJoinType, latest, "recent").
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `CROSS JOIN LATERAL (SELECT`)
+	})
+
+	t.Run("validates conditions and dialects", func(t *testing.T) {
+		tests := []struct {
+			name string
+			qb   *supersaiyan.SQLBuilder
+			err  error
+		}{
+			{
+				"inner without on",
+				supersaiyan.New("postgres", "users", "u").InnerJoin("orders", "o"),
+				supersaiyan.ErrInvalidJoin,
+			},
+			{
+				"cross with on",
+				supersaiyan.New("postgres", "users", "u").Join(
+					exp.CrossJoinType,
+					supersaiyan.Table{Name: "sizes", Alias: "s"},
+					supersaiyan.Eq("id", "s", 1),
+				),
+				supersaiyan.ErrInvalidJoin,
+			},
+			{
+				"lateral without query",
+				supersaiyan.New("postgres", "users", "u").
+					LateralJoin(exp.InnerJoinType, nil, "recent"),
+				supersaiyan.ErrInvalidJoin,
+			},
+			{
+				"on value that is not a condition",
+				func() *supersaiyan.SQLBuilder {
+					qb := supersaiyan.New("postgres", "users", "u")
+					qb.Table.Relations = append(qb.Table.Relations, supersaiyan.Relation{
+						JoinType: exp.InnerJoinType,
+						Table:    supersaiyan.Table{Name: "orders", Alias: "o"},
+						On: []any{
+							supersaiyan.Eq("user_id", "o", userID),
+							`"o"."status" = 'paid'`,
+						},
+					})
+					return qb
+				}(),
+				supersaiyan.ErrInvalidJoin,
+			},
+			{
+				"full outer on mysql",
+				supersaiyan.New("mysql", "users", "u").
+					FullJoin("accounts", "a", supersaiyan.Eq("user_id", "a", userID)),
+				supersaiyan.ErrUnsupportedByDialect,
+			},
+			{
+				"natural on sqlserver",
+				supersaiyan.New("sqlserver", "users", "u").NaturalJoin("profiles", "p"),
+				supersaiyan.ErrUnsupportedByDialect,
+			},
+			{
+				"lateral on sqlite",
+				supersaiyan.New("sqlite3", "users", "u").LateralJoin(
+					exp.InnerJoinType,
+					supersaiyan.New("sqlite3", "orders", "o"),
+					"recent",
+				),
+				supersaiyan.ErrUnsupportedByDialect,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, _, err := tt.qb.Select()
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("unmarshals join documents", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: users
+  alias: u
+  relations:
+    - joinType: FULL OUTER
+      on:
+        - op: eq
+          fieldName: user_id
+          tableAlias: a
+          value: {name: id, tableAlias: u}
+      table: {name: accounts, alias: a}
+    - joinType: cross
+      table: {name: sizes, alias: s}
+    - joinType: LEFT
+      lateral: true
+      query:
+        dialect: postgres
+        table: {name: orders, alias: o}
+        wheres:
+          - op: eq
+            fieldName: user_id
+            tableAlias: o
+            value: {name: id, tableAlias: u}
+      table: {alias: recent}
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`FULL OUTER JOIN "accounts" AS "a" ON ("a"."user_id" = "u"."id") `+
+				`CROSS JOIN "sizes" AS "s" `+
+				`LEFT JOIN LATERAL (SELECT * FROM "orders" AS "o" `+
+				`WHERE ("o"."user_id" = "u"."id")) AS "recent" ON TRUE`,
+			sql,
+		)
+
+		jsonBytes, err := json.Marshal(qb.Table.Relations[2])
+		require.NoError(t, err)
+		var relation supersaiyan.Relation
+		require.NoError(t, json.Unmarshal(jsonBytes, &relation))
+		assert.Equal(t, exp.LeftJoinType, relation.JoinType)
+		assert.True(t, relation.Lateral)
+		require.NotNil(t, relation.Query)
+		assert.Equal(t, "orders", relation.Query.Table.Name)
+	})
+}
+
+// TestJoinKeys tests the USING and keys join shorthands
+func TestJoinKeys(t *testing.T) {
+	t.Run("renders using per dialect", func(t *testing.T) {
+		for dialect, expected := range map[string]string{
+			"postgres": `INNER JOIN "accounts" AS "a" USING ("tenant_id", "user_id")`,
+			"sqlserver": `INNER JOIN "accounts" AS "a" ON (("a"."tenant_id" = "u"."tenant_id") ` +
+				`AND ("a"."user_id" = "u"."user_id"))`,
+		} {
+			sql, _, err := supersaiyan.New(dialect, "users", "u").
+				JoinUsing(exp.InnerJoinType, "accounts", "a", "tenant_id", "user_id").
+				Limit(0).
+				Select()
+			require.NoError(t, err)
+			assert.Equal(t, `SELECT * FROM "users" AS "u" `+expected, sql, dialect)
+		}
+	})
+
+	t.Run("expands keys against the parent table", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			JoinKeys(exp.LeftJoinType, "orders", "o", map[string]string{
+				"user_id":   "id",
+				"tenant_id": "tenant_id",
+			}).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" LEFT JOIN "orders" AS "o" `+
+				`ON (("o"."tenant_id" = "u"."tenant_id") AND ("o"."user_id" = "u"."id"))`,
+			sql,
+		)
+		assert.Empty(t, args)
+	})
+
+	t.Run("unmarshals nested shorthands", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: users
+  alias: u
+  relations:
+    - joinType: INNER
+      keys: {user_id: id, tenant_id: tenant_id}
+      on:
+        - op: eq
+          fieldName: status
+          tableAlias: o
+          value: paid
+      table:
+        name: orders
+        alias: o
+        relations:
+          - joinType: LEFT
+            keys: {order_id: id, tenant_id: u.tenant_id}
+            table: {name: order_items, alias: i}
+          - joinType: INNER
+            using: [sku]
+            table: {name: products, alias: p}
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`INNER JOIN "orders" AS "o" ON (("o"."status" = ?) AND `+
+				`("o"."tenant_id" = "u"."tenant_id") AND ("o"."user_id" = "u"."id")) `+
+				`LEFT JOIN "order_items" AS "i" ON (("i"."order_id" = "o"."id") AND `+
+				`("i"."tenant_id" = "u"."tenant_id")) `+
+				`INNER JOIN "products" AS "p" USING ("sku")`,
+			sql,
+		)
+		assert.Equal(t, []any{"paid"}, args)
+
+		jsonBytes, err := json.Marshal(qb.Table.Relations[0].Table.Relations[1])
+		require.NoError(t, err)
+		var relation supersaiyan.Relation
+		require.NoError(t, json.Unmarshal(jsonBytes, &relation))
+		assert.Equal(t, []string{"sku"}, relation.Using)
+	})
+
+	t.Run("rejects using with other conditions", func(t *testing.T) {
+		relation := supersaiyan.Relation{
+			JoinType: exp.InnerJoinType,
+			Using:    []string{"tenant_id"},
+			Keys:     map[string]string{"user_id": "id"},
+			Table:    supersaiyan.Table{Name: "orders", Alias: "o"},
+		}
+		qb := supersaiyan.New("postgres", "users", "u")
+		qb.Table.Relations = append(qb.Table.Relations, relation)
+		_, _, err := qb.Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidJoin)
+
+		_, _, err = supersaiyan.New("postgres", "users", "u").
+			JoinUsing(exp.CrossJoinType, "sizes", "s", "id").
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidJoin)
+	})
+}
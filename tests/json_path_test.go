@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJSONPath tests JSON path extraction and the JSON operators
+func TestJSONPath(t *testing.T) {
+	attrs := supersaiyan.F("attrs", supersaiyan.WithTable("u"))
+
+	t.Run("extracts paths per dialect", func(t *testing.T) {
+		tests := []struct {
+			dialect  string
+			path     supersaiyan.JSONPath
+			expected string
+			args     []any
+		}{
+			{
+				"postgres", supersaiyan.JSONText(attrs, "address", "city"),
+				`("u"."attrs" -> ? ->> ?)`, []any{"address", "city", "Paris"},
+			},
+			{
+				"postgres", supersaiyan.JSONGet(attrs, "tags", 0),
+				`("u"."attrs" -> ? -> 0)`, []any{"tags", "Paris"},
+			},
+			{
+				"postgres", supersaiyan.JSONText(attrs, "phones", 2),
+				`("u"."attrs" -> ? ->> 2)`, []any{"phones", "Paris"},
+			},
+			{
+				"mysql", supersaiyan.JSONText(attrs, "address", "city"),
+				`JSON_UNQUOTE(JSON_EXTRACT("u"."attrs", ?))`, []any{"$.address.city", "Paris"},
+			},
+			{
+				"mysql", supersaiyan.JSONGet(attrs, "zip code", 1),
+				`JSON_EXTRACT("u"."attrs", ?)`, []any{`$."zip code"[1]`, "Paris"},
+			},
+			{
+				"mysql", supersaiyan.JSONGet(attrs, `a\"b`),
+				`JSON_EXTRACT("u"."attrs", ?)`, []any{`$."a\\\"b"`, "Paris"},
+			},
+			{
+				"sqlite3", supersaiyan.JSONText(attrs, "address", "city"),
+				`JSON_EXTRACT("u"."attrs", ?)`, []any{"$.address.city", "Paris"},
+			},
+			{
+				"sqlserver", supersaiyan.JSONText(attrs, "address", "city"),
+				`JSON_VALUE("u"."attrs", ?)`, []any{"$.address.city", "Paris"},
+			},
+			{
+				"sqlserver", supersaiyan.JSONGet(attrs, "address"),
+				`JSON_QUERY("u"."attrs", ?)`, []any{"$.address", "Paris"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.dialect+"/"+tt.expected, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "users", "u").
+					Where(supersaiyan.BoolOp{Op: exp.EqOp, Left: tt.path, Value: "Paris"}).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE (`+tt.expected+` = ?)`, sql)
+				assert.Equal(t, tt.args, args)
+			})
+		}
+	})
+
+	t.Run("selects paths with aliases", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithFields(supersaiyan.Exp("city", supersaiyan.JSONText(attrs, "address", "city"))).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT ("u"."attrs" -> ? ->> ?) AS "city" FROM "users" AS "u"`, sql)
+	})
+
+	t.Run("renders containment and key existence", func(t *testing.T) {
+		tests := []struct {
+			dialect  string
+			cond     supersaiyan.BoolOp
+			expected string
+			args     []any
+		}{
+			{
+				"postgres", supersaiyan.JSONContains("attrs", "u", map[string]any{"role": "admin"}),
+				`("u"."attrs" @> ?::jsonb)`, []any{`{"role":"admin"}`},
+			},
+			{
+				"mysql", supersaiyan.JSONContains("attrs", "u", `{"role": "admin"}`),
+				`JSON_CONTAINS("u"."attrs", ?)`, []any{`{"role": "admin"}`},
+			},
+			{
+				"postgres", supersaiyan.JSONHasKey("attrs", "u", "phone"),
+				`JSONB_EXISTS("u"."attrs", ?)`, []any{"phone"},
+			},
+			{
+				"mysql", supersaiyan.JSONHasKey("attrs", "u", "phone"),
+				`JSON_CONTAINS_PATH("u"."attrs", 'one', ?)`, []any{"$.phone"},
+			},
+			{
+				"sqlite3", supersaiyan.JSONHasKey("attrs", "u", "phone"),
+				`(JSON_TYPE("u"."attrs", ?) IS NOT NULL)`, []any{"$.phone"},
+			},
+			{
+				"sqlserver", supersaiyan.JSONHasKey("attrs", "u", "phone"),
+				`(JSON_PATH_EXISTS("u"."attrs", ?) = 1)`, []any{"$.phone"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.dialect+"/"+tt.expected, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "users", "u").
+					Where(tt.cond).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE `+tt.expected, sql)
+				assert.Equal(t, tt.args, args)
+			})
+		}
+	})
+
+	t.Run("reports unsupported operators and invalid paths", func(t *testing.T) {
+		for _, dialect := range []string{"sqlite3", "sqlserver"} {
+			_, _, err := supersaiyan.New(dialect, "users", "u").
+				Where(supersaiyan.JSONContains("attrs", "u", map[string]any{"role": "admin"})).
+				Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect, dialect)
+		}
+
+		for _, path := range []supersaiyan.JSONPath{
+			supersaiyan.JSONGet(attrs),
+			supersaiyan.JSONGet(attrs, -1),
+			supersaiyan.JSONGet(attrs, true),
+		} {
+			_, _, err := supersaiyan.New("mysql", "users", "u").
+				WithFields(supersaiyan.Exp("v", path)).
+				Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidExpression)
+		}
+	})
+
+	t.Run("unmarshals JSON documents", func(t *testing.T) {
+		jsonData := `{
+			"op": "AND",
+			"conditions": [
+				{"op": "eq", "left": {"json": {"name": "attrs", "tableAlias": "u"}, "path": ["address", "city"], "text": true}, "value": "Paris"},
+				{"op": "jsonContains", "fieldName": "attrs", "tableAlias": "u", "value": {"role": "admin"}},
+				{"op": "jsonHasKey", "fieldName": "attrs", "tableAlias": "u", "value": "phone"}
+			]
+		}`
+
+		var group supersaiyan.WhereGroup
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &group))
+		assert.Equal(t, supersaiyan.And(
+			supersaiyan.BoolOp{
+				Op:    exp.EqOp,
+				Left:  supersaiyan.JSONText(attrs, "address", "city"),
+				Value: "Paris",
+			},
+			supersaiyan.JSONContains("attrs", "u", map[string]any{"role": "admin"}),
+			supersaiyan.JSONHasKey("attrs", "u", "phone"),
+		), group)
+
+		jsonBytes, err := json.Marshal(group)
+		require.NoError(t, err)
+		var roundTrip supersaiyan.WhereGroup
+		require.NoError(t, json.Unmarshal(jsonBytes, &roundTrip))
+		assert.Equal(t, group, roundTrip)
+	})
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestLikeHelpers tests literal text matching with escaped LIKE patterns
+func TestLikeHelpers(t *testing.T) {
+	t.Run("escapes wildcards per dialect", func(t *testing.T) {
+		tests := []struct {
+			dialect  string
+			cond     supersaiyan.BoolOp
+			expected string
+			arg      string
+		}{
+			{
+				"postgres", supersaiyan.Contains("name", "u", "50%_off"),
+				`("u"."name" LIKE ?)`, `%50\%\_off%`,
+			},
+			{
+				"mysql", supersaiyan.StartsWith("path", "u", `C:\tmp`),
+				`("u"."path" LIKE ?)`, `C:\\tmp%`,
+			},
+			{
+				"sqlite3", supersaiyan.EndsWith("email", "u", "_x.com"),
+				`("u"."email" LIKE ? ESCAPE '\')`, `%\_x.com`,
+			},
+			{
+				"sqlserver", supersaiyan.Contains("name", "u", "[draft]"),
+				`("u"."name" LIKE ? ESCAPE '\')`, `%\[draft]%`,
+			},
+			{
+				"sqlserver", supersaiyan.Like("name", "u", `jo\%%`),
+				`("u"."name" LIKE ? ESCAPE '\')`, `jo\%%`,
+			},
+			{
+				"sqlite3", supersaiyan.ILike("name", "u", "jo%"),
+				`(LOWER("u"."name") LIKE LOWER(?) ESCAPE '\')`, "jo%",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.dialect+"/"+tt.arg, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "users", "u").
+					Where(tt.cond).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE `+tt.expected, sql)
+				assert.Equal(t, []any{tt.arg}, args)
+			})
+		}
+	})
+
+	t.Run("rejects non-string values", func(t *testing.T) {
+		cond := supersaiyan.BoolOp{
+			Op:         supersaiyan.ContainsOp,
+			FieldName:  "name",
+			TableAlias: "u",
+			Value:      42,
+		}
+		_, _, err := supersaiyan.New("postgres", "users", "u").Where(cond).Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidCondition)
+	})
+
+	t.Run("unmarshals like operators", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: users
+  alias: u
+wheres:
+  - op: contains
+    fieldName: name
+    tableAlias: u
+    value: "50%"
+  - op: startsWith
+    fieldName: code
+    tableAlias: u
+    value: A_
+  - op: endsWith
+    fieldName: email
+    tableAlias: u
+    value: "@x.com"
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		assert.Equal(t, []any{
+			supersaiyan.Contains("name", "u", "50%"),
+			supersaiyan.StartsWith("code", "u", "A_"),
+			supersaiyan.EndsWith("email", "u", "@x.com"),
+		}, qb.Wheres)
+
+		jsonBytes, err := json.Marshal(qb.Wheres[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(jsonBytes), `"op":"startsWith"`)
+	})
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestParseMongoFilter tests decoding of MongoDB-style filter documents
+func TestParseMongoFilter(t *testing.T) {
+	parse := func(t *testing.T, doc string) (supersaiyan.Condition, error) {
+		return supersaiyan.ParseMongoFilter([]byte(doc), supersaiyan.AllowAnyField())
+	}
+
+	t.Run("combines fields with AND in document order", func(t *testing.T) {
+		cond, err := parse(t, `{"status": "active", "age": {"$gt": 18}, "deleted_at": null}`)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("status", "", "active"),
+			supersaiyan.Gt("age", "", float64(18)),
+			supersaiyan.IsNull("deleted_at", ""),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("translates comparison operators", func(t *testing.T) {
+		tests := []struct {
+			doc      string
+			expected supersaiyan.Condition
+		}{
+			{`{"a": {"$eq": "x"}}`, supersaiyan.Eq("a", "", "x")},
+			{
+				`{"a": {"$ne": "x"}}`,
+				supersaiyan.Or(supersaiyan.Neq("a", "", "x"), supersaiyan.IsNull("a", "")),
+			},
+			{`{"a": {"$ne": null}}`, supersaiyan.IsNotNull("a", "")},
+			{`{"a": {"$gte": 1}}`, supersaiyan.Gte("a", "", float64(1))},
+			{`{"a": {"$lt": 1}}`, supersaiyan.Lt("a", "", float64(1))},
+			{`{"a": {"$lte": 1}}`, supersaiyan.Lte("a", "", float64(1))},
+			{`{"a": {"$in": ["x", "y"]}}`, supersaiyan.In("a", "", []any{"x", "y"})},
+			{
+				`{"a": {"$nin": ["x"]}}`,
+				supersaiyan.Or(supersaiyan.NotIn("a", "", []any{"x"}), supersaiyan.IsNull("a", "")),
+			},
+			{`{"a": {"$exists": true}}`, supersaiyan.IsNotNull("a", "")},
+			{`{"a": {"$exists": false}}`, supersaiyan.IsNull("a", "")},
+			{
+				`{"a": {"$regex": "^jo"}}`,
+				supersaiyan.BoolOp{Op: exp.RegexpLikeOp, FieldName: "a", Value: "^jo"},
+			},
+			{
+				`{"a": {"$regex": "^jo", "$options": "i"}}`,
+				supersaiyan.BoolOp{Op: exp.RegexpILikeOp, FieldName: "a", Value: "^jo"},
+			},
+			{
+				`{"a": {"$gte": 18, "$lte": 65}}`,
+				supersaiyan.Between("a", "", float64(18), float64(65)),
+			},
+			{
+				`{"a": {"$gt": 1, "$lt": 5}}`,
+				supersaiyan.And(
+					supersaiyan.Gt("a", "", float64(1)),
+					supersaiyan.Lt("a", "", float64(5)),
+				),
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.doc, func(t *testing.T) {
+				cond, err := parse(t, tt.doc)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, cond)
+			})
+		}
+	})
+
+	t.Run("translates logical operators", func(t *testing.T) {
+		cond, err := parse(t, `{
+			"status": "active",
+			"$or": [{"role": {"$in": ["admin", "mod"]}}, {"age": {"$gte": 18}}]
+		}`)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("status", "", "active"),
+			supersaiyan.Or(
+				supersaiyan.In("role", "", []any{"admin", "mod"}),
+				supersaiyan.Gte("age", "", float64(18)),
+			),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("negates with $not and $nor", func(t *testing.T) {
+		cond, err := parse(t, `{"age": {"$not": {"$gte": 18, "$lte": 65}}}`)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Or(
+			supersaiyan.NotBetween("age", "", float64(18), float64(65)),
+			supersaiyan.IsNull("age", ""),
+		), cond)
+
+		cond, err = parse(t, `{"$nor": [{"status": "banned"}, {"age": {"$lt": 18}}]}`)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.And(
+			supersaiyan.Or(
+				supersaiyan.Neq("status", "", "banned"),
+				supersaiyan.IsNull("status", ""),
+			),
+			supersaiyan.Or(supersaiyan.Gte("age", "", float64(18)), supersaiyan.IsNull("age", "")),
+		), cond)
+
+		cond, err = parse(t, `{"name": {"$not": {"$regex": "^a", "$options": "i"}}}`)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Or(
+			supersaiyan.BoolOp{Op: exp.RegexpNotILikeOp, FieldName: "name", Value: "^a"},
+			supersaiyan.IsNull("name", ""),
+		), cond)
+
+		cond, err = parse(t, `{"deleted_at": {"$not": {"$exists": true}}}`)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.IsNull("deleted_at", ""), cond)
+	})
+
+	t.Run("matches NULL on negations like MongoDB", func(t *testing.T) {
+		cond, err := parse(t, `{"status": {"$ne": "banned"}}`)
+		require.NoError(t, err)
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").Where(cond).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" WHERE (("status" != ?) OR ("status" IS NULL))`,
+			sql,
+		)
+		assert.Equal(t, []any{"banned"}, args)
+	})
+
+	t.Run("resolves fields through a field map", func(t *testing.T) {
+		fields := supersaiyan.FieldMap{"name": supersaiyan.F("username", supersaiyan.WithTable("u"))}
+
+		cond, err := supersaiyan.ParseMongoFilter([]byte(`{"name": "john"}`), fields)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("username", "u", "john"), cond)
+
+		_, err = supersaiyan.ParseMongoFilter([]byte(`{"$or": [{"password": "x"}]}`), fields)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("rejects malformed documents", func(t *testing.T) {
+		invalid := []string{
+			`{}`,
+			`[]`,
+			`{"$where": "1"}`,
+			`{"a": {"$near": 1}}`,
+			`{"a": {"$in": "x"}}`,
+			`{"a": {"$exists": 1}}`,
+			`{"a": {"$gt": null}}`,
+			`{"a": {"$options": "i"}}`,
+			`{"a": {"$not": 5}}`,
+			`{"a": ["x"]}`,
+			`{"$or": []}`,
+			`{"a": 1, "a": 2}`,
+			`{"a": {"$gt": 1, "$gt": 5}}`,
+			`{"$or": [{"a": 1, "b": 2, "a": 3}]}`,
+			`{"a": {"$not": {"$lt": 1, "$lt": 2}}}`,
+			`{"a": 1}{"b": 2}`,
+			`{"a": 1} junk`,
+			`{"a": {"$in": []}}`,
+			`{"a": {"$nin": []}}`,
+		}
+		for _, doc := range invalid {
+			_, err := parse(t, doc)
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidFilter, doc)
+		}
+	})
+
+	t.Run("is accepted in JSON and YAML documents", func(t *testing.T) {
+		jsonStr := `{
+			"dialect": "postgres",
+			"table": {"name": "users", "alias": "u"},
+			"wheres": [
+				{"op": "eq", "fieldName": "status", "tableAlias": "u", "value": "active"},
+				{"u.age": {"$gte": 18}, "$or": [{"u.role": "admin"}, {"u.verified": true}]}
+			]
+		}`
+
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, json.Unmarshal([]byte(jsonStr), &qb))
+
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`WHERE (("u"."status" = ?) AND (("u"."age" >= ?) AND (("u"."role" = ?) OR ("u"."verified" = ?))))`,
+		)
+		assert.Equal(t, []any{"active", float64(18), "admin", true}, args)
+
+		yamlStr := `
+dialect: postgres
+table:
+  name: users
+  alias: u
+wheres:
+  - u.age:
+      $gte: 18
+`
+		var fromYAML supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &fromYAML))
+		require.Len(t, fromYAML.Wheres, 1)
+		assert.Equal(t, supersaiyan.Gte("age", "u", float64(18)), fromYAML.Wheres[0])
+	})
+}
@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseODataFilter tests translation of OData $filter expressions
+func TestParseODataFilter(t *testing.T) {
+	t.Run("translates comparisons and logical operators", func(t *testing.T) {
+		cond, err := supersaiyan.ParseODataFilter(
+			"Status eq 'active' and (Age ge 18 or Role in ('admin', 'mod'))",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("Status", "", "active"),
+			supersaiyan.Or(
+				supersaiyan.Gte("Age", "", int64(18)),
+				supersaiyan.In("Role", "", []any{"admin", "mod"}),
+			),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("translates string functions into escaped LIKE patterns", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected supersaiyan.Condition
+		}{
+			{"contains(Name, '50%')", supersaiyan.Like("Name", "", `%50\%%`)},
+			{"startswith(Name,'Jo')", supersaiyan.Like("Name", "", "Jo%")},
+			{"endswith(Email, '_x.com')", supersaiyan.Like("Email", "", `%\_x.com`)},
+			{
+				"not contains(Name, 'bot')",
+				supersaiyan.BoolOp{Op: exp.NotLikeOp, FieldName: "Name", Value: "%bot%"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				cond, err := supersaiyan.ParseODataFilter(tt.input, supersaiyan.AllowAnyField())
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, cond)
+			})
+		}
+	})
+
+	t.Run("negates expressions with not", func(t *testing.T) {
+		cond, err := supersaiyan.ParseODataFilter(
+			"not (Age gt 5 or Status eq 'banned')",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.Not(supersaiyan.Or(
+			supersaiyan.Gt("Age", "", int64(5)),
+			supersaiyan.Eq("Status", "", "banned"),
+		))
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("translates literals", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected supersaiyan.Condition
+		}{
+			{"DeletedAt eq null", supersaiyan.IsNull("DeletedAt", "")},
+			{"Email ne null", supersaiyan.IsNotNull("Email", "")},
+			{"Price lt -2.5", supersaiyan.Lt("Price", "", -2.5)},
+			{"Active eq true", supersaiyan.Eq("Active", "", true)},
+			{
+				"CreatedAt gt 2024-01-01",
+				supersaiyan.Gt("CreatedAt", "", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			{
+				"CreatedAt le 2024-01-01T10:30:00Z",
+				supersaiyan.Lte("CreatedAt", "", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)),
+			},
+			{"Price gt Cost", supersaiyan.Gt("Price", "", supersaiyan.F("Cost"))},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				cond, err := supersaiyan.ParseODataFilter(tt.input, supersaiyan.AllowAnyField())
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, cond)
+			})
+		}
+	})
+
+	t.Run("resolves property paths through a field map", func(t *testing.T) {
+		fields := supersaiyan.FieldMap{
+			"Name":         supersaiyan.F("username", supersaiyan.WithTable("u")),
+			"Address/City": supersaiyan.F("city", supersaiyan.WithTable("a")),
+		}
+
+		cond, err := supersaiyan.ParseODataFilter("Address/City eq 'Oslo'", fields)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("city", "a", "Oslo"), cond)
+
+		_, err = supersaiyan.ParseODataFilter("Password eq 'x'", fields)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("rejects unsupported constructs", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			column int
+		}{
+			{"tolower(Name) eq 'x'", 1},
+			{"Price add 5 gt 10", 7},
+			{"Price gt 5 add 1", 12},
+			{"Tags/any(t: t eq 'x')", 1},
+			{"contains(Name, 'x') eq true", 21},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				_, err := supersaiyan.ParseODataFilter(tt.input, supersaiyan.AllowAnyField())
+				require.ErrorIs(t, err, supersaiyan.ErrUnsupportedOData)
+
+				var syntaxErr *supersaiyan.SyntaxError
+				require.ErrorAs(t, err, &syntaxErr)
+				assert.Equal(t, tt.column, syntaxErr.Column, err.Error())
+			})
+		}
+	})
+
+	t.Run("reports syntax errors", func(t *testing.T) {
+		invalid := []string{"Age gt", "Age gtx 5", "(Age gt 5", "Name eq 'x", "Age gt 5)"}
+		for _, input := range invalid {
+			_, err := supersaiyan.ParseODataFilter(input, supersaiyan.AllowAnyField())
+
+			var syntaxErr *supersaiyan.SyntaxError
+			assert.ErrorAs(t, err, &syntaxErr, input)
+		}
+	})
+}
+
+// TestApplyOData tests applying OData query options to a builder
+func TestApplyOData(t *testing.T) {
+	fields := supersaiyan.FieldMap{
+		"Id":        supersaiyan.F("id", supersaiyan.WithTable("u")),
+		"Name":      supersaiyan.F("username", supersaiyan.WithTable("u")),
+		"CreatedAt": supersaiyan.F("created_at", supersaiyan.WithTable("u")),
+	}
+
+	t.Run("applies filter, orderby, select, top and skip", func(t *testing.T) {
+		values := url.Values{
+			"$filter":  {"startswith(Name, 'Jo')"},
+			"$orderby": {"CreatedAt desc, Name"},
+			"$select":  {"Id,Name"},
+			"$top":     {"20"},
+			"$skip":    {"40"},
+			"other":    {"ignored"},
+		}
+
+		qb := supersaiyan.New("postgres", "users", "u")
+		require.NoError(t, qb.ApplyOData(values, fields))
+
+		sql, args, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT "u"."id" AS "Id", "u"."username" AS "Name" FROM "users" AS "u" `+
+				`WHERE ("u"."username" LIKE ?) `+
+				`ORDER BY "u"."created_at" DESC, "u"."username" ASC LIMIT ? OFFSET ?`,
+			sql,
+		)
+		assert.Equal(t, []any{"Jo%", int64(20), int64(40)}, args)
+	})
+
+	t.Run("selects unmapped fields without an alias", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u")
+		values := url.Values{"$select": {"u.name"}}
+		require.NoError(t, qb.ApplyOData(values, supersaiyan.AllowAnyField()))
+
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT "u"."name" FROM "users" AS "u" LIMIT ?`, sql)
+	})
+
+	t.Run("rejects unsupported query options", func(t *testing.T) {
+		qb := supersaiyan.New("postgres", "users", "u")
+		err := qb.ApplyOData(url.Values{"$expand": {"Orders"}}, fields)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedOData)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		tests := []struct {
+			values url.Values
+			target error
+		}{
+			{url.Values{"$top": {"-1"}}, supersaiyan.ErrInvalidParameter},
+			{url.Values{"$top": {"0"}}, supersaiyan.ErrInvalidParameter},
+			{url.Values{"$skip": {"x"}}, supersaiyan.ErrInvalidParameter},
+			{url.Values{"$orderby": {"Name sideways"}}, supersaiyan.ErrInvalidSort},
+			{url.Values{"$orderby": {"Password"}}, supersaiyan.ErrUnknownField},
+			{url.Values{"$select": {"Id,Password"}}, supersaiyan.ErrUnknownField},
+		}
+
+		for _, tt := range tests {
+			qb := supersaiyan.New("postgres", "users", "u")
+			err := qb.ApplyOData(tt.values, fields)
+			assert.ErrorIs(t, err, tt.target, tt.values.Encode())
+		}
+	})
+}
@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSorts tests parsing of REST-style sort strings
+func TestParseSorts(t *testing.T) {
+	fields := supersaiyan.FieldMap{
+		"created_at": supersaiyan.F("created_at", supersaiyan.WithTable("u")),
+		"name":       supersaiyan.F("username", supersaiyan.WithTable("u")),
+		"orders":     supersaiyan.F("", supersaiyan.WithAlias("order_count")),
+	}
+
+	t.Run("parses prefix directions", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("-created_at,+name", fields)
+		require.NoError(t, err)
+		require.Len(t, sorts, 2)
+
+		assert.Equal(t, supersaiyan.Desc("created_at", "u"), sorts[0])
+		assert.Equal(t, supersaiyan.Asc("username", "u"), sorts[1])
+	})
+
+	t.Run("parses suffix directions", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("created_at:DESC, name:asc", fields)
+		require.NoError(t, err)
+		require.Len(t, sorts, 2)
+
+		assert.Equal(t, exp.DescSortDir, sorts[0].Order)
+		assert.Equal(t, exp.AscDir, sorts[1].Order)
+	})
+
+	t.Run("maps alias-only fields to alias sorts", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("-orders", fields)
+		require.NoError(t, err)
+		require.Len(t, sorts, 1)
+
+		assert.Equal(t, supersaiyan.DescAlias("order_count"), sorts[0])
+	})
+
+	t.Run("ignores empty terms", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts(" name ,, ", fields)
+		require.NoError(t, err)
+		assert.Len(t, sorts, 1)
+
+		sorts, err = supersaiyan.ParseSorts("", fields)
+		require.NoError(t, err)
+		assert.Empty(t, sorts)
+	})
+
+	t.Run("rejects fields outside the allow-list", func(t *testing.T) {
+		_, err := supersaiyan.ParseSorts("-password", fields)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("rejects malformed terms", func(t *testing.T) {
+		invalid := []string{"-name:asc", "name:sideways", "-", "name,-name", ":desc"}
+		for _, input := range invalid {
+			_, err := supersaiyan.ParseSorts(input, fields)
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidSort, input)
+		}
+	})
+
+	t.Run("accepts qualified names only when opted in", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("-u.created_at,id", supersaiyan.AllowAnyField())
+		require.NoError(t, err)
+		require.Len(t, sorts, 2)
+
+		assert.Equal(t, supersaiyan.Desc("created_at", "u"), sorts[0])
+		assert.Equal(t, supersaiyan.Asc("id", ""), sorts[1])
+
+		_, err = supersaiyan.ParseSorts("-u.created_at", nil)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+
+		_, err = supersaiyan.ParseFilter("u.password = 'x'", nil)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("treats a star entry as an ordinary name", func(t *testing.T) {
+		starred := supersaiyan.FieldMap{"*": supersaiyan.F("id", supersaiyan.WithTable("u"))}
+
+		_, err := supersaiyan.ParseSorts("-password", starred)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+
+		_, err = supersaiyan.ParseFilter("u.password = 'x'", starred)
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("AllowFields builds an identity mapping", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("-id", supersaiyan.AllowFields("u", "id", "email"))
+		require.NoError(t, err)
+		assert.Equal(t, []supersaiyan.Sort{supersaiyan.Desc("id", "u")}, sorts)
+
+		_, err = supersaiyan.ParseSorts("username", supersaiyan.AllowFields("u", "id"))
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+	})
+
+	t.Run("parsed sorts render in queries", func(t *testing.T) {
+		sorts, err := supersaiyan.ParseSorts("-created_at,name", fields)
+		require.NoError(t, err)
+
+		sql, _, err := supersaiyan.New("postgres", "users", "u").OrderBy(sorts...).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `ORDER BY "u"."created_at" DESC, "u"."username" ASC`)
+	})
+}
+
+// TestParseFilter tests parsing of the filter expression language
+func TestParseFilter(t *testing.T) {
+	t.Run("parses nested AND/OR groups", func(t *testing.T) {
+		cond, err := supersaiyan.ParseFilter(
+			"status = 'active' and (age >= 18 or role in ('admin', 'mod'))",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("status", "", "active"),
+			supersaiyan.Or(
+				supersaiyan.Gte("age", "", int64(18)),
+				supersaiyan.In("role", "", []any{"admin", "mod"}),
+			),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("gives AND precedence over OR", func(t *testing.T) {
+		cond, err := supersaiyan.ParseFilter(
+			"a = 1 or b = 2 and c = 3",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.Or(
+			supersaiyan.Eq("a", "", int64(1)),
+			supersaiyan.And(
+				supersaiyan.Eq("b", "", int64(2)),
+				supersaiyan.Eq("c", "", int64(3)),
+			),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("negates terms with not", func(t *testing.T) {
+		cond, err := supersaiyan.ParseFilter(
+			"not (role = 'guest' or banned = true) and age > 18",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Not(supersaiyan.Or(
+				supersaiyan.Eq("role", "", "guest"),
+				supersaiyan.Eq("banned", "", true),
+			)),
+			supersaiyan.Gt("age", "", int64(18)),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("parses keyword operators", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected supersaiyan.Condition
+		}{
+			{"deleted_at is null", supersaiyan.IsNull("deleted_at", "")},
+			{"email IS NOT NULL", supersaiyan.IsNotNull("email", "")},
+			{"deleted_at = null", supersaiyan.IsNull("deleted_at", "")},
+			{"verified is true", supersaiyan.BoolOp{Op: exp.IsOp, FieldName: "verified", Value: true}},
+			{"email like '%@example.com'", supersaiyan.Like("email", "", "%@example.com")},
+			{"name ILIKE '%john%'", supersaiyan.ILike("name", "", "%john%")},
+			{"name not like 'a%'", supersaiyan.BoolOp{Op: exp.NotLikeOp, FieldName: "name", Value: "a%"}},
+			{"role not in ('banned')", supersaiyan.NotIn("role", "", []any{"banned"})},
+			{"age between 18 and 65", supersaiyan.Between("age", "", int64(18), int64(65))},
+			{"price not between 1.5 and -2", supersaiyan.NotBetween("price", "", 1.5, int64(-2))},
+			{"name !~* '^j'", supersaiyan.BoolOp{Op: exp.RegexpNotILikeOp, FieldName: "name", Value: "^j"}},
+			{"role <> 'guest'", supersaiyan.Neq("role", "", "guest")},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				cond, err := supersaiyan.ParseFilter(tt.input, supersaiyan.AllowAnyField())
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, cond)
+			})
+		}
+	})
+
+	t.Run("unescapes quoted strings", func(t *testing.T) {
+		cond, err := supersaiyan.ParseFilter("name = 'O''Brien'", supersaiyan.AllowAnyField())
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("name", "", "O'Brien"), cond)
+	})
+
+	t.Run("resolves names through a field map", func(t *testing.T) {
+		fields := supersaiyan.FieldMap{"name": supersaiyan.F("username", supersaiyan.WithTable("u"))}
+
+		cond, err := supersaiyan.ParseFilter("name = 'john'", fields)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("username", "u", "john"), cond)
+
+		_, err = supersaiyan.ParseFilter("name = 'john' and password = 'x'", fields)
+		require.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+
+		var syntaxErr *supersaiyan.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, 19, syntaxErr.Column)
+	})
+
+	t.Run("compares expression fields on their expression", func(t *testing.T) {
+		total := supersaiyan.L(`"o"."price" * "o"."qty"`)
+		fields := supersaiyan.FieldMap{"total": supersaiyan.Exp("total", total)}
+
+		cond, err := supersaiyan.ParseFilter("total > 100 and total between 1 and 5", fields)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.BoolOp{Op: exp.GtOp, Left: total, Value: int64(100)},
+			supersaiyan.RangeOp{Op: exp.BetweenOp, Left: total, Start: int64(1), End: int64(5)},
+		)
+		assert.Equal(t, expected, cond)
+
+		sql, _, err := supersaiyan.New("postgres", "orders", "o").Where(cond).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `("o"."price" * "o"."qty" > ?)`)
+	})
+
+	t.Run("reports syntax errors with column offsets", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			column int
+		}{
+			{"status = ", 10},
+			{"status = 'active", 10},
+			{"(age > 1", 9},
+			{"age > 1)", 8},
+			{"age between 1 or 2", 15},
+			{"role in 'admin'", 9},
+			{"age > null", 5},
+			{"age # 3", 5},
+			{"and = 1", 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				_, err := supersaiyan.ParseFilter(tt.input, supersaiyan.AllowAnyField())
+
+				var syntaxErr *supersaiyan.SyntaxError
+				require.ErrorAs(t, err, &syntaxErr)
+				assert.Equal(t, tt.column, syntaxErr.Column, err.Error())
+			})
+		}
+	})
+
+	t.Run("parsed filters render in queries", func(t *testing.T) {
+		cond, err := supersaiyan.ParseFilter(
+			"u.status = 'active' and u.age >= 18",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").Where(cond).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE (("u"."status" = ?) AND ("u"."age" >= ?))`)
+		assert.Equal(t, []any{"active", int64(18)}, args)
+	})
+}
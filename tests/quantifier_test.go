@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuantifiedComparisons tests ANY / ALL / SOME comparisons against lists and subqueries
+func TestQuantifiedComparisons(t *testing.T) {
+	competitorPrices := supersaiyan.New("postgres", "competitor_products", "c").
+		WithFields(supersaiyan.F("price", supersaiyan.WithTable("c"))).
+		Where(supersaiyan.Eq("active", "c", true))
+
+	t.Run("binds a list as one array parameter on postgres", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.Eq("id", "u", []int{1, 2, 3}).Any()).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `WHERE "u"."id" = ANY(?)`)
+		assert.Equal(t, []any{"{1,2,3}"}, args)
+	})
+
+	t.Run("quotes string array elements", func(t *testing.T) {
+		_, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.Neq("role", "u", []any{"a\"b", `c\d`, nil}).All()).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(t, []any{`{"a\"b","c\\d",NULL}`}, args)
+	})
+
+	t.Run("compares against a subquery", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("mysql", "products", "p").
+			Where(supersaiyan.Gt("price", "p", competitorPrices).All()).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`WHERE "p"."price" > ALL (SELECT "c"."price" FROM "competitor_products" AS "c" WHERE ("c"."active" = ?))`,
+		)
+		assert.Equal(t, []any{true}, args)
+
+		sql, _, err = supersaiyan.New("postgres", "products", "p").
+			Where(supersaiyan.Lte("price", "p", competitorPrices).Some()).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `"p"."price" <= SOME (SELECT`)
+	})
+
+	t.Run("falls back to IN and NOT IN for lists elsewhere", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("mysql", "users", "u").
+			Where(
+				supersaiyan.Eq("id", "u", []int{1, 2}).Any(),
+				supersaiyan.Neq("role", "u", []string{"guest"}).All(),
+			).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `("u"."id" IN ((?, ?)))`)
+		assert.Contains(t, sql, `("u"."role" NOT IN ((?)))`)
+	})
+
+	t.Run("reports unsupported combinations", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			dialect string
+			cond    supersaiyan.BoolOp
+			err     error
+		}{
+			{
+				name:    "list with > on mysql",
+				dialect: "mysql",
+				cond:    supersaiyan.Gt("a", "u", []int{1}).Any(),
+				err:     supersaiyan.ErrUnsupportedByDialect,
+			},
+			{
+				name:    "subquery on sqlite",
+				dialect: "sqlite3",
+				cond:    supersaiyan.Eq("a", "u", competitorPrices).Any(),
+				err:     supersaiyan.ErrUnsupportedByDialect,
+			},
+			{
+				name:    "quantified LIKE",
+				dialect: "postgres",
+				cond:    supersaiyan.Like("a", "u", "x").Any(),
+				err:     supersaiyan.ErrInvalidCondition,
+			},
+			{
+				name:    "unknown quantifier",
+				dialect: "postgres",
+				cond:    supersaiyan.BoolOp{Op: exp.EqOp, FieldName: "a", Quantifier: "EVERY"},
+				err:     supersaiyan.ErrInvalidCondition,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, _, err := supersaiyan.New(tt.dialect, "users", "u").Where(tt.cond).Select()
+				assert.ErrorIs(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("reports errors from Edit and Delete", func(t *testing.T) {
+		qb := supersaiyan.New("sqlite3", "users", "u").
+			Where(supersaiyan.Eq("id", "u", competitorPrices).Any())
+
+		_, _, err := qb.Edit(map[string]any{"active": false})
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+
+		_, _, err = qb.Delete()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+	})
+
+	t.Run("unmarshals quantifier and subquery documents", func(t *testing.T) {
+		jsonData := `{
+			"op": "gt",
+			"fieldName": "price",
+			"tableAlias": "p",
+			"quantifier": "ALL",
+			"value": {
+				"dialect": "postgres",
+				"table": {"name": "competitor_products", "alias": "c"},
+				"fields": [{"name": "price", "tableAlias": "c"}]
+			}
+		}`
+
+		var boolOp supersaiyan.BoolOp
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &boolOp))
+		assert.Equal(t, supersaiyan.AllQuantifier, boolOp.Quantifier)
+		assert.IsType(t, supersaiyan.SQLBuilder{}, boolOp.Value)
+
+		sql, _, err := supersaiyan.New("postgres", "products", "p").Where(boolOp).Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`"p"."price" > ALL (SELECT "c"."price" FROM "competitor_products" AS "c")`,
+		)
+
+		jsonBytes, err := json.Marshal(supersaiyan.Eq("id", "u", []int{1}).Any())
+		require.NoError(t, err)
+		assert.Contains(t, string(jsonBytes), `"quantifier":"ANY"`)
+	})
+}
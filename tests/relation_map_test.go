@@ -0,0 +1,324 @@
+package tests
+
+import (
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const relationsYAML = `
+orders:
+  joinType: LEFT
+  keys: {user_id: id}
+  table: {name: orders, alias: o}
+profile:
+  joinType: INNER
+  keys: {user_id: id}
+  table:
+    name: profiles
+    alias: p
+    relations:
+      - joinType: LEFT
+        keys: {id: country_id}
+        table: {name: countries, alias: c}
+`
+
+// TestRelationMap tests joining relations declared once by name
+func TestRelationMap(t *testing.T) {
+	relations, err := supersaiyan.ParseRelations([]byte(relationsYAML))
+	require.NoError(t, err)
+
+	t.Run("parses nested relations from YAML", func(t *testing.T) {
+		require.Len(t, relations, 2)
+		profile := relations["profile"]
+		assert.Equal(t, exp.InnerJoinType, profile.JoinType)
+		assert.Equal(t, map[string]string{"user_id": "id"}, profile.Keys)
+		assert.Equal(t, "profiles", profile.Table.Name)
+		require.Len(t, profile.Table.Relations, 1)
+		assert.Equal(t, exp.LeftJoinType, profile.Table.Relations[0].JoinType)
+		assert.Equal(
+			t,
+			supersaiyan.Table{Name: "countries", Alias: "c"},
+			profile.Table.Relations[0].Table,
+		)
+
+		_, err := supersaiyan.ParseRelations([]byte(`
+orders:
+  joinType: LEFT
+  keys: {user_id: id}
+  table:
+    name: orders
+    alias: o
+    relations:
+      - joinType: LEFT
+        keys: {order_id: id}
+        table: {name: order_items}
+`))
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidJoin)
+
+		_, err = supersaiyan.ParseRelations([]byte(`orders: [1, 2]`))
+		assert.Error(t, err)
+	})
+
+	t.Run("expands named joins", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			JoinNamed("orders", "profile").
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`LEFT JOIN "orders" AS "o" ON ("o"."user_id" = "u"."id") `+
+				`INNER JOIN "profiles" AS "p" ON ("p"."user_id" = "u"."id") `+
+				`LEFT JOIN "countries" AS "c" ON ("c"."id" = "p"."country_id")`,
+			sql,
+		)
+	})
+
+	t.Run("skips relations already joined", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			InnerJoin("orders", "o", supersaiyan.Eq("user_id", "o", 1)).
+			JoinNamed("orders", "orders").
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" INNER JOIN "orders" AS "o" ON ("o"."user_id" = ?)`,
+			sql,
+		)
+	})
+
+	t.Run("reads join names from documents", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table: {name: users, alias: u}
+join: [profile]
+wheres:
+  - op: eq
+    fieldName: code
+    tableAlias: c
+    value: NL
+  - op: in
+    fieldName: id
+    tableAlias: u
+    value:
+      dialect: postgres
+      fields: [{name: id, tableAlias: buyer}]
+      table: {name: users, alias: buyer}
+      join: [orders]
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		assert.Equal(t, []string{"profile"}, qb.Joins)
+
+		sql, args, err := qb.WithRelations(relations).Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`INNER JOIN "profiles" AS "p" ON ("p"."user_id" = "u"."id") `+
+				`LEFT JOIN "countries" AS "c" ON ("c"."id" = "p"."country_id") `+
+				`WHERE (("c"."code" = ?) AND ("u"."id" IN ((SELECT "buyer"."id" FROM "users" `+
+				`AS "buyer" LEFT JOIN "orders" AS "o" ON ("o"."user_id" = "buyer"."id")))))`,
+			sql,
+		)
+		assert.Equal(t, []any{"NL"}, args)
+	})
+
+	t.Run("rejects aliases joined to another table", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			InnerJoin("offices", "o", supersaiyan.Eq("id", "o", 1)).
+			JoinNamed("orders").
+			Select()
+		require.ErrorIs(t, err, supersaiyan.ErrInvalidJoin)
+		assert.Contains(t, err.Error(), `already joined to "offices"`)
+	})
+
+	t.Run("rejects unknown names", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			JoinNamed("invoices").
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownRelation)
+
+		_, _, err = supersaiyan.New("postgres", "users", "u").JoinNamed("orders").Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnknownRelation)
+	})
+
+	t.Run("declares relations in Go", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(supersaiyan.RelationMap{
+				"accounts": {
+					JoinType: exp.InnerJoinType,
+					Using:    []string{"tenant_id"},
+					Table:    supersaiyan.Table{Name: "accounts", Alias: "a"},
+				},
+			}).
+			JoinNamed("accounts").
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" INNER JOIN "accounts" AS "a" USING ("tenant_id")`,
+			sql,
+		)
+	})
+}
+
+const relationGraphYAML = `
+orders:
+  joinType: LEFT
+  keys: {user_id: id}
+  table: {name: orders, alias: o}
+items:
+  joinType: LEFT
+  keys: {order_id: o.id}
+  table: {name: order_items, alias: i}
+products:
+  joinType: INNER
+  on:
+    - op: eq
+      fieldName: id
+      tableAlias: pr
+      value: {name: product_id, tableAlias: i}
+  table: {name: products, alias: pr}
+profile:
+  joinType: INNER
+  keys: {user_id: id}
+  table:
+    name: profiles
+    alias: p
+    relations:
+      - joinType: LEFT
+        keys: {id: country_id}
+        table: {name: countries, alias: c}
+`
+
+// TestJoinInference tests joining referenced aliases along the declared relation graph
+func TestJoinInference(t *testing.T) {
+	relations, err := supersaiyan.ParseRelations([]byte(relationGraphYAML))
+	require.NoError(t, err)
+
+	t.Run("joins the path to referenced aliases", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			WithFields(
+				supersaiyan.F("name", supersaiyan.WithTable("u")),
+				supersaiyan.Exp("total", supersaiyan.Sum(supersaiyan.F(
+					"price",
+					supersaiyan.WithTable("pr"),
+				))),
+			).
+			Where(supersaiyan.Eq("code", "c", "NL")).
+			GroupByFields(supersaiyan.F("name", supersaiyan.WithTable("u"))).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT "u"."name", SUM("pr"."price") AS "total" FROM "users" AS "u" `+
+				`LEFT JOIN "orders" AS "o" ON ("o"."user_id" = "u"."id") `+
+				`LEFT JOIN "order_items" AS "i" ON ("i"."order_id" = "o"."id") `+
+				`INNER JOIN "products" AS "pr" ON ("pr"."id" = "i"."product_id") `+
+				`INNER JOIN "profiles" AS "p" ON ("p"."user_id" = "u"."id") `+
+				`LEFT JOIN "countries" AS "c" ON ("c"."id" = "p"."country_id") `+
+				`WHERE ("c"."code" = ?) GROUP BY "u"."name"`,
+			sql,
+		)
+		assert.Equal(t, []any{"NL"}, args)
+	})
+
+	t.Run("does not repeat joined relations", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			JoinNamed("orders").
+			Where(supersaiyan.Gt("quantity", "i", 1)).
+			OrderBy(supersaiyan.Desc("created_at", "o")).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`LEFT JOIN "orders" AS "o" ON ("o"."user_id" = "u"."id") `+
+				`LEFT JOIN "order_items" AS "i" ON ("i"."order_id" = "o"."id") `+
+				`WHERE ("i"."quantity" > ?) ORDER BY "o"."created_at" DESC`,
+			sql,
+		)
+	})
+
+	t.Run("leaves unknown and outer aliases alone", func(t *testing.T) {
+		recent := supersaiyan.New("postgres", "orders", "o").
+			WithFields(supersaiyan.F("user_id", supersaiyan.WithTable("o"))).
+			Where(supersaiyan.Eq(
+				"country_id",
+				"o",
+				supersaiyan.F("id", supersaiyan.WithTable("c")),
+			))
+
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			WithRelations(relations).
+			Where(
+				supersaiyan.Eq("code", "c", "NL"),
+				supersaiyan.In("id", "u", *recent),
+				supersaiyan.Eq("flag", "x", true),
+			).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" `+
+				`INNER JOIN "profiles" AS "p" ON ("p"."user_id" = "u"."id") `+
+				`LEFT JOIN "countries" AS "c" ON ("c"."id" = "p"."country_id") `+
+				`WHERE (("c"."code" = ?) AND ("u"."id" IN ((SELECT "o"."user_id" `+
+				`FROM "orders" AS "o" WHERE ("o"."country_id" = "c"."id")))) AND ("x"."flag" = ?))`,
+			sql,
+		)
+	})
+
+	t.Run("rejects ambiguous paths", func(t *testing.T) {
+		relations := supersaiyan.RelationMap{
+			"billing": {
+				JoinType: exp.LeftJoinType,
+				Keys:     map[string]string{"id": "billing_address_id"},
+				Table:    supersaiyan.Table{Name: "addresses", Alias: "a"},
+			},
+			"shipping": {
+				JoinType: exp.LeftJoinType,
+				Keys:     map[string]string{"id": "shipping_address_id"},
+				Table:    supersaiyan.Table{Name: "addresses", Alias: "a"},
+			},
+		}
+		_, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithRelations(relations).
+			Where(supersaiyan.Eq("city", "a", "Paris")).
+			Select()
+		require.ErrorIs(t, err, supersaiyan.ErrAmbiguousRelation)
+		assert.Contains(t, err.Error(), `"billing" and "shipping"`)
+
+		sql, _, err := supersaiyan.New("postgres", "orders", "o").
+			WithRelations(relations).
+			JoinNamed("shipping").
+			Where(supersaiyan.Eq("city", "a", "Paris")).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(
+			t,
+			sql,
+			`LEFT JOIN "addresses" AS "a" ON ("a"."id" = "o"."shipping_address_id")`,
+		)
+	})
+}
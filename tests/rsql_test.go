@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseRSQL tests parsing of RSQL/FIQL queries
+func TestParseRSQL(t *testing.T) {
+	t.Run("parses AND/OR with AND precedence", func(t *testing.T) {
+		cond, err := supersaiyan.ParseRSQL(
+			"name==John;age=gt=30,role=in=(admin,mod)",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.Or(
+			supersaiyan.And(
+				supersaiyan.Eq("name", "", "John"),
+				supersaiyan.Gt("age", "", int64(30)),
+			),
+			supersaiyan.In("role", "", []any{"admin", "mod"}),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("parses parenthesized groups", func(t *testing.T) {
+		cond, err := supersaiyan.ParseRSQL(
+			"status==active;(age>=18 , verified==true)",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("status", "", "active"),
+			supersaiyan.Or(
+				supersaiyan.Gte("age", "", int64(18)),
+				supersaiyan.Eq("verified", "", "true"),
+			),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("parses all comparison operators", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected supersaiyan.Condition
+		}{
+			{"a!=x", supersaiyan.Neq("a", "", "x")},
+			{"a=lt=1.5", supersaiyan.Lt("a", "", 1.5)},
+			{"a<1", supersaiyan.Lt("a", "", int64(1))},
+			{"a=le=1", supersaiyan.Lte("a", "", int64(1))},
+			{"a<=1", supersaiyan.Lte("a", "", int64(1))},
+			{"a>1", supersaiyan.Gt("a", "", int64(1))},
+			{"a=ge=1", supersaiyan.Gte("a", "", int64(1))},
+			{"a=out=(x, 'y z')", supersaiyan.NotIn("a", "", []any{"x", "y z"})},
+			{"a=like=%x%", supersaiyan.Like("a", "", "%x%")},
+			{"a=ilike=%x%", supersaiyan.ILike("a", "", "%x%")},
+			{"a=isnull=true", supersaiyan.IsNull("a", "")},
+			{"a=isnull=false", supersaiyan.IsNotNull("a", "")},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				cond, err := supersaiyan.ParseRSQL(tt.input, supersaiyan.AllowAnyField())
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, cond)
+			})
+		}
+	})
+
+	t.Run("parses quoted arguments", func(t *testing.T) {
+		cond, err := supersaiyan.ParseRSQL(
+			`title=="Hello, \"world\"";code=='42'`,
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("title", "", `Hello, "world"`),
+			supersaiyan.Eq("code", "", "42"),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("reads only plain decimals as numbers", func(t *testing.T) {
+		cond, err := supersaiyan.ParseRSQL(
+			"a==-12;b==1.5e3;c==nan;d==Infinity;e==1_000;f==+5;g==0x1F",
+			supersaiyan.AllowAnyField(),
+		)
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Eq("a", "", int64(-12)),
+			supersaiyan.Eq("b", "", 1500.0),
+			supersaiyan.Eq("c", "", "nan"),
+			supersaiyan.Eq("d", "", "Infinity"),
+			supersaiyan.Eq("e", "", "1_000"),
+			supersaiyan.Eq("f", "", "+5"),
+			supersaiyan.Eq("g", "", "0x1F"),
+		)
+		assert.Equal(t, expected, cond)
+	})
+
+	t.Run("turns wildcards into escaped LIKE patterns", func(t *testing.T) {
+		cond, err := supersaiyan.ParseRSQL("name==Jo*;code!=*_x", supersaiyan.AllowAnyField())
+		require.NoError(t, err)
+
+		expected := supersaiyan.And(
+			supersaiyan.Like("name", "", "Jo%"),
+			supersaiyan.BoolOp{Op: exp.NotLikeOp, FieldName: "code", Value: `%\_x`},
+		)
+		assert.Equal(t, expected, cond)
+
+		cond, err = supersaiyan.ParseRSQL(`name=="Jo*"`, supersaiyan.AllowAnyField())
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("name", "", "Jo*"), cond)
+	})
+
+	t.Run("resolves selectors through a field map", func(t *testing.T) {
+		fields := supersaiyan.FieldMap{"name": supersaiyan.F("username", supersaiyan.WithTable("u"))}
+
+		cond, err := supersaiyan.ParseRSQL("name==john", fields)
+		require.NoError(t, err)
+		assert.Equal(t, supersaiyan.Eq("username", "u", "john"), cond)
+
+		_, err = supersaiyan.ParseRSQL("name==john;password==x", fields)
+		require.ErrorIs(t, err, supersaiyan.ErrUnknownField)
+
+		var syntaxErr *supersaiyan.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+		assert.Equal(t, 12, syntaxErr.Column)
+	})
+
+	t.Run("reports syntax errors with column offsets", func(t *testing.T) {
+		tests := []struct {
+			input  string
+			column int
+		}{
+			{"name", 5},
+			{"name=foo=bar", 5},
+			{"name==", 7},
+			{"(name==a", 9},
+			{"name==a)", 8},
+			{`name=="open`, 7},
+			{"role=in=admin", 9},
+			{"a=isnull=maybe", 10},
+			{";a==b", 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				_, err := supersaiyan.ParseRSQL(tt.input, supersaiyan.AllowAnyField())
+
+				var syntaxErr *supersaiyan.SyntaxError
+				require.ErrorAs(t, err, &syntaxErr)
+				assert.Equal(t, tt.column, syntaxErr.Column, err.Error())
+			})
+		}
+	})
+}
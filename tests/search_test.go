@@ -0,0 +1,277 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestSearch tests full-text search conditions
+func TestSearch(t *testing.T) {
+	name := supersaiyan.F("name", supersaiyan.WithTable("p"))
+	summary := supersaiyan.F("summary", supersaiyan.WithTable("p"))
+
+	t.Run("renders per dialect", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			dialect  string
+			search   supersaiyan.Search
+			expected string
+			args     []any
+		}{
+			{
+				"plain", "postgres", supersaiyan.FullText("wireless mouse", name),
+				`(TO_TSVECTOR("p"."name") @@ PLAINTO_TSQUERY(?))`,
+				[]any{"wireless mouse"},
+			},
+			{
+				"language", "postgres",
+				supersaiyan.FullText("wireless mouse", name, summary).WithLanguage("english"),
+				`(TO_TSVECTOR('english', COALESCE("p"."name", '') || ' ' || ` +
+					`COALESCE("p"."summary", '')) @@ ` +
+					`PLAINTO_TSQUERY('english', ?))`,
+				[]any{"wireless mouse"},
+			},
+			{
+				"boolean", "postgres",
+				supersaiyan.FullText(`mouse -wired`, name).WithMode(supersaiyan.BooleanSearch),
+				`(TO_TSVECTOR("p"."name") @@ WEBSEARCH_TO_TSQUERY(?))`,
+				[]any{"mouse -wired"},
+			},
+			{
+				"plain", "mysql", supersaiyan.FullText(`wireless +mouse "pro"`, name, summary),
+				`MATCH ("p"."name", "p"."summary") AGAINST (? IN BOOLEAN MODE)`,
+				[]any{`+"wireless" +"mouse" +"pro"`},
+			},
+			{
+				"phrase", "mysql",
+				supersaiyan.FullText("usb c", name).WithMode(supersaiyan.PhraseSearch),
+				`MATCH ("p"."name") AGAINST (? IN BOOLEAN MODE)`,
+				[]any{`"usb c"`},
+			},
+			{
+				"plain", "sqlite3", supersaiyan.FullText(`wireless "mouse`, name),
+				`("p"."name" MATCH ?)`,
+				[]any{`"wireless" """mouse"`},
+			},
+			{
+				"columns", "sqlite3", supersaiyan.FullText("mouse", name, summary),
+				`(("p"."name" MATCH ?) OR ("p"."summary" MATCH ?))`,
+				[]any{`"mouse"`, `"mouse"`},
+			},
+			{
+				"plain", "sqlserver", supersaiyan.FullText("wireless mouse", name, summary),
+				`CONTAINS(("p"."name", "p"."summary"), ?)`,
+				[]any{`"wireless" AND "mouse"`},
+			},
+			{
+				"phrase", "sqlserver",
+				supersaiyan.FullText("usb  c", name).WithMode(supersaiyan.PhraseSearch),
+				`CONTAINS(("p"."name"), ?)`,
+				[]any{`"usb c"`},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name+"/"+tt.dialect, func(t *testing.T) {
+				sql, args, err := supersaiyan.New(tt.dialect, "products", "p").
+					Where(tt.search).
+					Limit(0).
+					Select()
+				require.NoError(t, err)
+				assert.Equal(t, `SELECT * FROM "products" AS "p" WHERE `+tt.expected, sql)
+				assert.Equal(t, tt.args, args)
+			})
+		}
+	})
+
+	t.Run("ranks results as a field and sort", func(t *testing.T) {
+		search := supersaiyan.FullText("mouse", name)
+		sql, args, err := supersaiyan.New("postgres", "products", "p").
+			WithFields(name, supersaiyan.Exp("relevance", search.Rank())).
+			Where(supersaiyan.And(search, supersaiyan.Eq("active", "p", true))).
+			OrderBy(supersaiyan.DescExp(search.Rank())).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT "p"."name", TS_RANK(TO_TSVECTOR("p"."name"), PLAINTO_TSQUERY(?)) AS "relevance" `+
+				`FROM "products" AS "p" `+
+				`WHERE ((TO_TSVECTOR("p"."name") @@ PLAINTO_TSQUERY(?)) AND ("p"."active" = ?)) `+
+				`ORDER BY TS_RANK(TO_TSVECTOR("p"."name"), PLAINTO_TSQUERY(?)) DESC`,
+			sql,
+		)
+		assert.Equal(t, []any{"mouse", "mouse", true, "mouse"}, args)
+
+		sql, _, err = supersaiyan.New("sqlite3", "products_fts", "p").
+			OrderBy(supersaiyan.DescExp(search.Rank())).
+			Where(search).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `ORDER BY (-"p"."rank") DESC`)
+
+		_, _, err = supersaiyan.New("sqlserver", "products", "p").
+			OrderBy(supersaiyan.DescExp(search.Rank())).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+	})
+
+	t.Run("rejects invalid searches", func(t *testing.T) {
+		for _, search := range []supersaiyan.Search{
+			supersaiyan.FullText("mouse"),
+			supersaiyan.FullText("   ", name),
+			supersaiyan.FullText("mouse", name).WithLanguage("english'); DROP TABLE x; --"),
+			supersaiyan.FullText("mouse", name).WithMode("fuzzy"),
+		} {
+			_, _, err := supersaiyan.New("postgres", "products", "p").Where(search).Select()
+			assert.ErrorIs(t, err, supersaiyan.ErrInvalidCondition)
+		}
+	})
+
+	t.Run("unmarshals search documents", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: products
+  alias: p
+fields:
+  - name: name
+    tableAlias: p
+  - fieldAlias: relevance
+    exp:
+      rank:
+        search: [{name: name, tableAlias: p}]
+        query: wireless mouse
+wheres:
+  - search:
+      - name: name
+        tableAlias: p
+      - name: summary
+        tableAlias: p
+    query: wireless mouse
+    mode: phrase
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		assert.Equal(
+			t,
+			supersaiyan.FullText("wireless mouse", name, summary).
+				WithMode(supersaiyan.PhraseSearch),
+			qb.Wheres[0],
+		)
+		assert.Equal(t, supersaiyan.FullText("wireless mouse", name).Rank(), qb.Fields[1].Exp)
+
+		jsonBytes, err := json.Marshal(qb.Wheres[0])
+		require.NoError(t, err)
+		var group supersaiyan.WhereGroup
+		require.NoError(t, json.Unmarshal(
+			[]byte(`{"op": "AND", "conditions": [`+string(jsonBytes)+`]}`),
+			&group,
+		))
+		assert.Equal(t, qb.Wheres[0], group.Conditions[0])
+	})
+}
+
+// TestSearchFields tests the multi-column search box helper
+func TestSearchFields(t *testing.T) {
+	name := supersaiyan.F("name", supersaiyan.WithTable("u"))
+	city := supersaiyan.F("city", supersaiyan.WithTable("a"))
+
+	t.Run("requires every term in any field", func(t *testing.T) {
+		group := supersaiyan.SearchFields(`  jo "new   york" 50%_off\ `, name, city)
+		assert.Equal(t, supersaiyan.And(
+			supersaiyan.Or(
+				supersaiyan.ILike("name", "u", "%jo%"),
+				supersaiyan.ILike("city", "a", "%jo%"),
+			),
+			supersaiyan.Or(
+				supersaiyan.ILike("name", "u", "%new york%"),
+				supersaiyan.ILike("city", "a", "%new york%"),
+			),
+			supersaiyan.Or(
+				supersaiyan.ILike("name", "u", `%50\%\_off\\%`),
+				supersaiyan.ILike("city", "a", `%50\%\_off\\%`),
+			),
+		), group)
+
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.SearchFields("jo smith", name, city)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" WHERE `+
+				`((("u"."name" ILIKE ?) OR ("a"."city" ILIKE ?)) AND `+
+				`(("u"."name" ILIKE ?) OR ("a"."city" ILIKE ?)))`,
+			sql,
+		)
+		assert.Equal(t, []any{"%jo%", "%jo%", "%smith%", "%smith%"}, args)
+	})
+
+	t.Run("emulates ILIKE with LOWER elsewhere", func(t *testing.T) {
+		tests := map[string]string{
+			"mysql": `((LOWER("u"."name") LIKE LOWER(?)) OR (LOWER("a"."city") LIKE LOWER(?)))`,
+			"sqlserver": `((LOWER("u"."name") LIKE LOWER(?) ESCAPE '\') OR ` +
+				`(LOWER("a"."city") LIKE LOWER(?) ESCAPE '\'))`,
+		}
+		for dialect, expected := range tests {
+			sql, args, err := supersaiyan.New(dialect, "users", "u").
+				Where(supersaiyan.SearchFields("Jo", name, city)).
+				Limit(0).
+				Select()
+			require.NoError(t, err)
+			assert.Equal(t, `SELECT * FROM "users" AS "u" WHERE `+expected, sql, dialect)
+			assert.Equal(t, []any{"%Jo%", "%Jo%"}, args)
+		}
+	})
+
+	t.Run("searches expression fields", func(t *testing.T) {
+		fullName := supersaiyan.Exp("full_name", supersaiyan.Concat(
+			supersaiyan.F("first_name", supersaiyan.WithTable("u")),
+			" ",
+			supersaiyan.F("last_name", supersaiyan.WithTable("u")),
+		))
+		sql, _, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.SearchFields("jo", fullName)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "users" AS "u" WHERE `+
+				`(("u"."first_name" || ? || "u"."last_name") ILIKE ?)`,
+			sql,
+		)
+	})
+
+	t.Run("adds no condition for blank input", func(t *testing.T) {
+		sql, args, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.SearchFields(`  "" `, name, city)).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(t, `SELECT * FROM "users" AS "u"`, sql)
+		assert.Empty(t, args)
+
+		_, _, err = supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.SearchFields(" ", name)).
+			Delete()
+		assert.ErrorIs(t, err, supersaiyan.ErrMissingWhereCondition)
+	})
+
+	t.Run("rejects a search without fields", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "users", "u").
+			Where(supersaiyan.SearchFields("jo")).
+			Limit(0).
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidCondition)
+	})
+}
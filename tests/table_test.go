@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"testing"
+
+	"supersaiyan"
+
+	"github.com/doug-martin/goqu/v9/exp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestQualifiedTables tests schema and database qualified table names
+func TestQualifiedTables(t *testing.T) {
+	t.Run("quotes each part of the name", func(t *testing.T) {
+		sql, _, err := supersaiyan.New("postgres", "events", "e").
+			WithSchema("analytics").
+			Join(
+				exp.InnerJoinType,
+				supersaiyan.Table{Name: "users", Schema: "auth", Alias: "u"},
+				supersaiyan.Eq("id", "u", supersaiyan.F("user_id", supersaiyan.WithTable("e"))),
+			).
+			Limit(0).
+			Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "analytics"."events" AS "e" `+
+				`INNER JOIN "auth"."users" AS "u" ON ("u"."id" = "e"."user_id")`,
+			sql,
+		)
+	})
+
+	t.Run("qualifies writes", func(t *testing.T) {
+		qb := supersaiyan.New("sqlserver", "events", "e").WithDatabase("warehouse", "dbo")
+
+		sql, _, err := qb.Add(map[string]any{"name": "signup"})
+		require.NoError(t, err)
+		assert.Equal(t, `INSERT INTO "warehouse"."dbo"."events" ("name") VALUES (?)`, sql)
+
+		qb.Where(supersaiyan.Eq("id", "", 7))
+		sql, _, err = qb.Edit(map[string]any{"name": "login"})
+		require.NoError(t, err)
+		assert.Equal(t, `UPDATE "warehouse"."dbo"."events" SET "name"=? WHERE ("id" = ?)`, sql)
+
+		sql, _, err = qb.Delete()
+		require.NoError(t, err)
+		assert.Equal(t, `DELETE FROM "warehouse"."dbo"."events" WHERE ("id" = ?)`, sql)
+	})
+
+	t.Run("validates database qualification", func(t *testing.T) {
+		_, _, err := supersaiyan.New("postgres", "events", "e").
+			WithDatabase("warehouse", "public").
+			Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+
+		qb := supersaiyan.New("sqlserver", "events", "e")
+		qb.Join(
+			exp.LeftJoinType,
+			supersaiyan.Table{Name: "users", Database: "crm", Alias: "u"},
+			supersaiyan.Eq("id", "u", 1),
+		)
+		_, _, err = qb.Select()
+		assert.ErrorIs(t, err, supersaiyan.ErrInvalidTable)
+
+		_, _, err = supersaiyan.New("mysql", "events", "e").
+			WithDatabase("warehouse", "dbo").
+			Add(map[string]any{"name": "signup"})
+		assert.ErrorIs(t, err, supersaiyan.ErrUnsupportedByDialect)
+	})
+
+	t.Run("unmarshals qualified tables", func(t *testing.T) {
+		yamlStr := `
+dialect: sqlserver
+table:
+  name: events
+  schema: dbo
+  database: warehouse
+  alias: e
+  relations:
+    - joinType: LEFT
+      keys: {id: user_id}
+      table: {name: users, schema: crm, alias: u}
+`
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		sql, _, err := qb.Select()
+		require.NoError(t, err)
+		assert.Equal(
+			t,
+			`SELECT * FROM "warehouse"."dbo"."events" AS "e" `+
+				`LEFT JOIN "crm"."users" AS "u" ON ("u"."id" = "e"."user_id")`,
+			sql,
+		)
+
+		out, err := yaml.Marshal(qb.Table)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "database: warehouse")
+		assert.Contains(t, string(out), "schema: crm")
+	})
+}
@@ -295,6 +295,74 @@ func TestUnmarshal_BoolOp(t *testing.T) {
 	}
 }
 
+// TestUnmarshal_LeftOperand tests documents with an expression as the left operand
+func TestUnmarshal_LeftOperand(t *testing.T) {
+	t.Run("JSON conditions with left expressions", func(t *testing.T) {
+		jsonData := `{
+			"dialect": "postgres",
+			"table": {"name": "orders", "alias": "o"},
+			"wheres": [
+				{"op": "eq", "left": {"value": "LOWER(?)", "args": [{"name": "email", "tableAlias": "o"}]}, "value": "x@y.z"},
+				{"op": "between", "left": {"value": "o.price * o.qty"}, "start": 10, "end": 100}
+			]
+		}`
+
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, json.Unmarshal([]byte(jsonData), &qb))
+		require.Len(t, qb.Wheres, 2)
+
+		boolOp, ok := qb.Wheres[0].(supersaiyan.BoolOp)
+		require.True(t, ok)
+		assert.Empty(t, boolOp.FieldName)
+		assert.IsType(t, supersaiyan.Literal{}, boolOp.Left)
+
+		rangeOp, ok := qb.Wheres[1].(supersaiyan.RangeOp)
+		require.True(t, ok)
+		assert.Equal(t, supersaiyan.L("o.price * o.qty"), rangeOp.Left)
+
+		sql, _, err := qb.Limit(0).Select()
+		require.NoError(t, err)
+		assert.Contains(t, sql, `(LOWER("o"."email") = ?)`)
+		assert.Contains(t, sql, `(o.price * o.qty BETWEEN ? AND ?)`)
+	})
+
+	t.Run("YAML condition with coalesce on the left", func(t *testing.T) {
+		yamlStr := `
+dialect: postgres
+table:
+  name: users
+  alias: u
+wheres:
+  - op: eq
+    left:
+      fields:
+        - name: nickname
+          tableAlias: u
+      defaultValue: anonymous
+    value: bob
+`
+
+		var qb supersaiyan.SQLBuilder
+		require.NoError(t, yaml.Unmarshal([]byte(yamlStr), &qb))
+		require.Len(t, qb.Wheres, 1)
+
+		boolOp, ok := qb.Wheres[0].(supersaiyan.BoolOp)
+		require.True(t, ok)
+		assert.IsType(t, supersaiyan.Coalesce{}, boolOp.Left)
+	})
+
+	t.Run("marshals left and omits an empty fieldName", func(t *testing.T) {
+		jsonBytes, err := json.Marshal(supersaiyan.BoolOp{
+			Op:    exp.GtOp,
+			Left:  supersaiyan.L("LENGTH(name)"),
+			Value: 3,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, string(jsonBytes), `"left":{"value":"LENGTH(name)"}`)
+		assert.NotContains(t, string(jsonBytes), "fieldName")
+	})
+}
+
 // TestUnmarshal_RangeOp tests unmarshaling of RangeOp
 func TestUnmarshal_RangeOp(t *testing.T) {
 	t.Run("between operation", func(t *testing.T) {
@@ -431,6 +499,20 @@ func TestUnmarshal_WhereGroup(t *testing.T) {
 		assert.Equal(t, exp.OrType, nestedGroup.Op)
 		assert.Len(t, nestedGroup.Conditions, 2)
 	})
+
+	t.Run("NOT group round-trips", func(t *testing.T) {
+		jsonData := `{"op":"NOT","conditions":[{"op":"eq","fieldName":"role","tableAlias":"u","value":"guest"}]}`
+
+		var whereGroup supersaiyan.WhereGroup
+		err := json.Unmarshal([]byte(jsonData), &whereGroup)
+		require.NoError(t, err)
+
+		assert.Equal(t, supersaiyan.Not(supersaiyan.Eq("role", "u", "guest")), whereGroup)
+
+		jsonBytes, err := json.Marshal(whereGroup)
+		require.NoError(t, err)
+		assert.Contains(t, string(jsonBytes), `"op":"NOT"`)
+	})
 }
 
 // TestUnmarshal_Case tests unmarshaling of Case expressions
@@ -1122,6 +1204,56 @@ groupBy:
 	})
 }
 
+// TestUnmarshal_Sort tests unmarshaling of Sort with expressions, aliases and NULLS placement
+func TestUnmarshal_Sort(t *testing.T) {
+	t.Run("JSON sort with expression and nulls", func(t *testing.T) {
+		jsonStr := `{
+			"exp": {"value": "LOWER(?)", "args": [{"name": "username", "tableAlias": "u"}]},
+			"order": "DESC",
+			"nulls": "last"
+		}`
+
+		var sort supersaiyan.Sort
+		err := json.Unmarshal([]byte(jsonStr), &sort)
+		require.NoError(t, err)
+
+		literal, ok := sort.Exp.(supersaiyan.Literal)
+		require.True(t, ok)
+		assert.Equal(t, "LOWER(?)", literal.Value)
+		assert.Equal(t, exp.DescSortDir, sort.Order)
+		assert.Equal(t, exp.NullsLastSortType, sort.Nulls)
+	})
+
+	t.Run("YAML sort with field alias", func(t *testing.T) {
+		yamlStr := `
+fieldAlias: order_count
+order: DESC
+nulls: FIRST
+`
+
+		var sort supersaiyan.Sort
+		err := yaml.Unmarshal([]byte(yamlStr), &sort)
+		require.NoError(t, err)
+
+		assert.Equal(t, "order_count", sort.FieldAlias)
+		assert.Equal(t, exp.DescSortDir, sort.Order)
+		assert.Equal(t, exp.NullsFirstSortType, sort.Nulls)
+	})
+
+	t.Run("round trips through JSON", func(t *testing.T) {
+		original := supersaiyan.AscExp(supersaiyan.L("RANDOM()")).NullsFirst()
+
+		data, err := json.Marshal(original)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"exp":{"value":"RANDOM()"},"order":"ASC","nulls":"FIRST"}`, string(data))
+
+		var restored supersaiyan.Sort
+		err = json.Unmarshal(data, &restored)
+		require.NoError(t, err)
+		assert.Equal(t, original, restored)
+	})
+}
+
 // TestUnmarshal_EdgeCases tests edge cases in unmarshaling
 func TestUnmarshal_EdgeCases(t *testing.T) {
 	t.Run("unmarshal case with simple value in else", func(t *testing.T) {
@@ -1266,6 +1398,10 @@ func TestMarshal_AllOperations(t *testing.T) {
 			{exp.NotLikeOp, "notLike"},
 			{exp.ILikeOp, "iLike"},
 			{exp.NotILikeOp, "notILike"},
+			{exp.RegexpLikeOp, "regexpLike"},
+			{exp.RegexpNotLikeOp, "regexpNotLike"},
+			{exp.RegexpILikeOp, "regexpILike"},
+			{exp.RegexpNotILikeOp, "regexpNotILike"},
 		}
 
 		for _, op := range operations {